@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/config"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+const usage = `usage: admin [flags] <command>
+
+commands:
+  migrate [up|down]  apply every pending migration, or revert the last one with down
+  seed               load the fixture warehouses and products into an empty database
+  reset              drop every table, apply the migrations and load the fixtures
+  status             print the schema version and the state of each migration
+
+flags:
+`
+
+func main() {
+	configPath := flag.String("config", "", "yaml or json config file, the env vars override its values")
+	storage := flag.String("storage", "", "storage backend: mysql, sqlite or postgres, overrides the config")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *storage != "" {
+		cfg.Storage.Backend = *storage
+	}
+	if err := cfg.ValidateDatabase(); err != nil {
+		log.Fatal(err)
+	}
+
+	dialect := database.Dialect(cfg.Storage.Backend)
+	var db *sql.DB
+	switch dialect {
+	case database.MySQL:
+		db, err = sql.Open("mysql", cfg.MySQLDSN())
+	case database.SQLite:
+		db, err = database.OpenSQLite(cfg.Database.SQLite.Path)
+	case database.Postgres:
+		db, err = database.OpenPostgres(cfg.Database.Postgres.DSN)
+	default:
+		log.Fatalf("storage %q has no schema to manage", cfg.Storage.Backend)
+	}
+	if err != nil {
+		log.Fatalf("open %s: %v", dialect, err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Database.ConnectTimeout))
+	err = database.Wait(ctx, db, nil)
+	cancel()
+	if err != nil {
+		db.Close()
+		log.Fatal(err)
+	}
+
+	migrator, err := database.NewMigrator(db, dialect)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := run(migrator, db, dialect, flag.Args()); err != nil {
+		db.Close()
+		log.Fatal(err)
+	}
+}
+
+// run executes the command in args
+func run(migrator *database.Migrator, db *sql.DB, dialect database.Dialect, args []string) error {
+	switch args[0] {
+	case "migrate":
+		direction := "up"
+		if len(args) > 1 {
+			direction = args[1]
+		}
+		switch direction {
+		case "up":
+			if err := migrator.Up(); err != nil {
+				return err
+			}
+		case "down":
+			if err := migrator.Down(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown migrate direction %q, use up or down", direction)
+		}
+		return printVersion(migrator)
+	case "seed":
+		if err := database.Seed(db, dialect); err != nil {
+			if errors.Is(err, database.ErrNotEmpty) {
+				return errors.New("the database already has rows, run reset to start over")
+			}
+			return err
+		}
+		log.Println("fixtures loaded")
+		return nil
+	case "reset":
+		if err := migrator.Reset(); err != nil {
+			return err
+		}
+		if err := database.Seed(db, dialect); err != nil {
+			return err
+		}
+		log.Println("fixtures loaded")
+		return printVersion(migrator)
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		if err := printVersion(migrator); err != nil {
+			return err
+		}
+		for _, migration := range status {
+			state := "pending"
+			if migration.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%04d %-30s %s\n", migration.Version, migration.Name, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, run admin -h for help", args[0])
+	}
+}
+
+// printVersion prints the current schema version
+func printVersion(migrator *database.Migrator) error {
+	version, err := migrator.Version()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("schema version %d\n", version)
+	return nil
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/product"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/warehouse"
+	"github.com/bootcamp-go/consignas-go-db.git/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+// statusClientClosedRequest es el status de un request que el cliente abandono antes
+// de la respuesta, no es un error del servidor y no se registra en el log
+const statusClientClosedRequest = 499
+
+// errorStatus devuelve el status http y el code que corresponden al error de un
+// servicio. Los codes son parte de la api, el frontend los usa en lugar del mensaje
+func errorStatus(err error) (int, string) {
+	var validation *domain.ValidationError
+	switch {
+	case errors.Is(err, product.ErrNotFound):
+		return http.StatusNotFound, "product_not_found"
+	case errors.Is(err, warehouse.ErrNotFound):
+		return http.StatusNotFound, "warehouse_not_found"
+	case errors.Is(err, warehouse.ErrHasProducts):
+		return http.StatusConflict, "warehouse_has_products"
+	case errors.Is(err, dberr.ErrDuplicateEntry), errors.Is(err, product.ErrAlreadyExist):
+		return http.StatusConflict, "duplicate_entry"
+	case errors.Is(err, dberr.ErrForeignKey):
+		return http.StatusConflict, "reference_not_found"
+	case errors.As(err, &validation):
+		return http.StatusUnprocessableEntity, "validation_failed"
+	case errors.Is(err, product.ErrParsingDate):
+		return http.StatusUnprocessableEntity, "invalid_date"
+	case errors.Is(err, dberr.ErrNotNullColumn):
+		return http.StatusUnprocessableEntity, "missing_field"
+	case errors.Is(err, dberr.ErrCanceled):
+		return statusClientClosedRequest, "request_canceled"
+	case errors.Is(err, dberr.ErrUnavailable), errors.Is(err, dberr.ErrDeadlock),
+		errors.Is(err, dberr.ErrAccessDenied), errors.Is(err, dberr.ErrNoDatabaseSelected):
+		return http.StatusServiceUnavailable, "service_unavailable"
+	default:
+		return http.StatusInternalServerError, "internal_error"
+	}
+}
+
+// failure escribe la respuesta de error de un servicio. Los errores 5xx se registran
+// en el log con su causa y al cliente solo le llega el texto del status
+func failure(c *gin.Context, err error) {
+	status, code := errorStatus(err)
+	if status >= http.StatusInternalServerError {
+		detail := err.Error()
+		var dbErr *dberr.Error
+		if errors.As(err, &dbErr) {
+			detail = dbErr.Detail()
+		}
+		log.Printf("%s %s request_id=%s: %s", c.Request.Method, c.Request.URL.Path, web.GetRequestID(c), detail)
+		if status == http.StatusInternalServerError {
+			err = errors.New("internal error")
+		} else {
+			err = errors.New("service unavailable")
+		}
+	}
+	web.FailureCode(c, status, code, err, fieldErrors(err)...)
+}
+
+// queryFailure escribe la respuesta de un query string con parametros invalidos
+func queryFailure(c *gin.Context, err error) {
+	web.FailureCode(c, http.StatusBadRequest, "invalid_query", err, fieldErrors(err)...)
+}
+
+// fieldErrors devuelve los problemas de cada campo de un ValidationError
+func fieldErrors(err error) []web.FieldError {
+	var fields []web.FieldError
+	var validation *domain.ValidationError
+	if errors.As(err, &validation) {
+		for _, field := range validation.Fields {
+			fields = append(fields, web.FieldError{Field: field.Field, Message: field.Message})
+		}
+	}
+	return fields
+}
+
+// bindFailure escribe la respuesta de un body que no se pudo leer
+func bindFailure(c *gin.Context) {
+	web.FailureCode(c, http.StatusBadRequest, "invalid_json", errors.New("invalid json"))
+}
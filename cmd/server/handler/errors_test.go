@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/product"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/warehouse"
+	"github.com/bootcamp-go/consignas-go-db.git/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubService es un product.Service que devuelve siempre err, List devuelve page y
+// Search devuelve hits, los dos guardan el pedido que reciben
+type stubService struct {
+	err    error
+	page   product.Page
+	query  product.ListQuery
+	hits   []product.SearchHit
+	search product.SearchQuery
+}
+
+func (s *stubService) GetByID(ctx context.Context, id int) (domain.Product, error) {
+	return domain.Product{}, s.err
+}
+
+func (s *stubService) GetByCode(ctx context.Context, code string) (domain.Product, error) {
+	return domain.Product{}, s.err
+}
+
+func (s *stubService) GetByCodes(ctx context.Context, codes []string) (product.CodeLookup, error) {
+	return product.CodeLookup{}, s.err
+}
+
+func (s *stubService) GetAll(ctx context.Context) ([]domain.Product, error) {
+	return nil, s.err
+}
+
+func (s *stubService) List(ctx context.Context, q product.ListQuery) (product.Page, error) {
+	s.query = q
+	return s.page, s.err
+}
+
+func (s *stubService) Search(ctx context.Context, q product.SearchQuery) ([]product.SearchHit, error) {
+	s.search = q
+	return s.hits, s.err
+}
+
+func (s *stubService) Create(ctx context.Context, p domain.Product) (domain.Product, error) {
+	return domain.Product{}, s.err
+}
+
+func (s *stubService) Delete(ctx context.Context, id int) error {
+	return s.err
+}
+
+func (s *stubService) Update(ctx context.Context, id int, p domain.Product) (domain.Product, error) {
+	return domain.Product{}, s.err
+}
+
+func (s *stubService) GetFullData(ctx context.Context, id int) (domain.ProductFull, error) {
+	return domain.ProductFull{}, s.err
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func TestFailure(t *testing.T) {
+	cases := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{"product not found", product.ErrNotFound, 404, "product_not_found", "product not found"},
+		{"warehouse not found", warehouse.ErrNotFound, 404, "warehouse_not_found", "warehouse not found"},
+		{"duplicate entry", &dberr.Error{Kind: dberr.ErrDuplicateEntry, Code: "1062", Cause: errors.New("Duplicate entry 'S82254D'")}, 409, "duplicate_entry", "duplicate entry"},
+		{"foreign key", &dberr.Error{Kind: dberr.ErrForeignKey, Code: "1452", Cause: errors.New("a foreign key constraint fails")}, 409, "reference_not_found", "referenced row does not exist"},
+		{"validation", &domain.ValidationError{Message: "invalid product", Fields: []domain.FieldError{{Field: "name", Message: "can't be empty"}}}, 422, "validation_failed", "invalid product: name can't be empty"},
+		{"parsing date", product.ErrParsingDate, 422, "invalid_date", "error parsing date"},
+		{"database unavailable", &dberr.Error{Kind: dberr.ErrUnavailable, Cause: errors.New("dial tcp: connection refused")}, 503, "service_unavailable", "service unavailable"},
+		{"access denied", &dberr.Error{Kind: dberr.ErrAccessDenied, Code: "1045", Cause: errors.New("Access denied for user 'root'")}, 503, "service_unavailable", "service unavailable"},
+		{"deadlock", &dberr.Error{Kind: dberr.ErrDeadlock, Code: "1213", Cause: errors.New("Deadlock found")}, 503, "service_unavailable", "service unavailable"},
+		{"canceled", &dberr.Error{Kind: dberr.ErrCanceled, Cause: context.Canceled}, 499, "request_canceled", "request canceled"},
+		{"internal", &dberr.Error{Kind: dberr.ErrInternal, Cause: errors.New("bad connection state")}, 500, "internal_error", "internal error"},
+		{"unknown error", errors.New("boom"), 500, "internal_error", "internal error"},
+		{"wrapped not found", fmt.Errorf("get product: %w", product.ErrNotFound), 404, "product_not_found", "get product: product not found"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			r := gin.New()
+			r.GET("/products/:id", NewProductHandler(&stubService{err: c.err}, "token", domain.NewDateFormats(true)).GetByID())
+			req := httptest.NewRequest(http.MethodGet, "/products/1", nil)
+			res := httptest.NewRecorder()
+
+			// act
+			r.ServeHTTP(res, req)
+
+			// assert
+			var problem web.Problem
+			assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+			assert.Equal(t, c.status, res.Code)
+			assert.Equal(t, web.ContentTypeProblem, res.Header().Get("Content-Type"))
+			assert.Equal(t, c.status, problem.Status)
+			assert.Equal(t, c.code, problem.Code)
+			assert.Equal(t, "/problems/"+c.code, problem.Type)
+			assert.Equal(t, c.message, problem.Detail)
+			assert.Equal(t, "/products/1", problem.Instance)
+		})
+	}
+}
+
+func TestFailure_Log(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		logged bool
+	}{
+		{"internal", &dberr.Error{Kind: dberr.ErrInternal, Cause: errors.New("bad connection state")}, true},
+		{"canceled", &dberr.Error{Kind: dberr.ErrCanceled, Cause: context.Canceled}, false},
+		{"not found", product.ErrNotFound, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			var logs strings.Builder
+			log.SetOutput(&logs)
+			defer log.SetOutput(io.Discard)
+			r := gin.New()
+			r.GET("/products/:id", NewProductHandler(&stubService{err: c.err}, "token", domain.NewDateFormats(true)).GetByID())
+
+			// act
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/products/1", nil))
+
+			// assert
+			assert.Equal(t, c.logged, logs.Len() > 0)
+		})
+	}
+}
+
+func TestProductHandler_Post_Validation(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+		fields []web.FieldError
+	}{
+		{"invalid json", `{"name":`, 400, "invalid_json", nil},
+		{"negative price", `{"name":"Oil","quantity":1,"code_value":"X1","expiration":"01/01/2030","price":-1,"id_warehouse":1}`, 422, "validation_failed", []web.FieldError{{Field: "price", Message: "must be greater than 0"}}},
+		{"invalid expiration", `{"name":"Oil","quantity":1,"code_value":"X1","expiration":"31/02/2030","price":1,"id_warehouse":1}`, 422, "validation_failed", []web.FieldError{{Field: "expiration", Message: `invalid date "31/02/2030", use yyyy-mm-dd`}}},
+		{"every violation", `{"name":"","expiration":"99/99/2024"}`, 422, "validation_failed", []web.FieldError{
+			{Field: "name", Message: "can't be empty"},
+			{Field: "code_value", Message: "can't be empty"},
+			{Field: "quantity", Message: "must be greater than 0"},
+			{Field: "price", Message: "must be greater than 0"},
+			{Field: "expiration", Message: `invalid date "99/99/2024", use yyyy-mm-dd`},
+			{Field: "id_warehouse", Message: "must be greater than 0"},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			r := gin.New()
+			r.Use(web.RequestID())
+			r.POST("/products", NewProductHandler(&stubService{}, "token", domain.NewDateFormats(true)).Post())
+			req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(c.body))
+			req.Header.Set("TOKEN", "token")
+			req.Header.Set(web.HeaderRequestID, "req-1")
+			res := httptest.NewRecorder()
+
+			// act
+			r.ServeHTTP(res, req)
+
+			// assert
+			var problem web.Problem
+			assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+			assert.Equal(t, c.status, res.Code)
+			assert.Equal(t, c.code, problem.Code)
+			assert.Equal(t, c.fields, problem.Errors)
+			assert.Equal(t, "req-1", problem.RequestID)
+			assert.Equal(t, "req-1", res.Header().Get(web.HeaderRequestID))
+		})
+	}
+}
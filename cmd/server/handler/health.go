@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/health"
+	"github.com/gin-gonic/gin"
+)
+
+type healthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *healthHandler {
+	return &healthHandler{
+		checker: checker,
+	}
+}
+
+// Live responde 200 mientras el proceso atienda requests, no revisa las dependencias
+func (h *healthHandler) Live() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, health.Report{Status: health.Up, Checks: map[string]health.Check{}})
+	}
+}
+
+// Ready corre los checks de las dependencias y responde 503 si alguno falla, asi el
+// orquestador deja de mandar trafico a la instancia
+func (h *healthHandler) Ready() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := h.checker.Run(c.Request.Context())
+		status := http.StatusOK
+		if report.Status != health.Up {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
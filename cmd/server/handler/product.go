@@ -2,9 +2,7 @@ package handler
 
 import (
 	"errors"
-	"os"
 	"strconv"
-	"strings"
 
 	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
 	"github.com/bootcamp-go/consignas-go-db.git/internal/product"
@@ -13,13 +11,19 @@ import (
 )
 
 type productHandler struct {
-	s product.Service
+	s     product.Service
+	token string
+	dates domain.DateFormats
 }
 
-// NewProductHandler crea un nuevo controller de productos
-func NewProductHandler(s product.Service) *productHandler {
+// NewProductHandler crea un nuevo controller de productos, token es el que deben
+// enviar en el header TOKEN los endpoints que modifican datos y dates los formatos en
+// que se aceptan las fechas
+func NewProductHandler(s product.Service, token string, dates domain.DateFormats) *productHandler {
 	return &productHandler{
-		s: s,
+		s:     s,
+		token: token,
+		dates: dates,
 	}
 }
 
@@ -29,116 +33,131 @@ func (h *productHandler) GetByID() gin.HandlerFunc {
 		idParam := c.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
-			web.Failure(c, 400, errors.New("invalid id"))
+			web.FailureCode(c, 400, "invalid_id", errors.New("invalid id"))
 			return
 		}
-		product, err := h.s.GetByID(id)
+		product, err := h.s.GetByID(c.Request.Context(), id)
 		if err != nil {
-			web.Failure(c, 404, errors.New("product not found"))
+			failure(c, err)
 			return
 		}
 		web.Success(c, 200, product)
 	}
 }
 
-// Get obtiene todos los productos
-func (h *productHandler) GetAll() gin.HandlerFunc {
+// GetByCode obtiene un producto por su code_value
+func (h *productHandler) GetByCode() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		products, err := h.s.GetAll()
+		product, err := h.s.GetByCode(c.Request.Context(), c.Param("code"))
 		if err != nil {
-			web.Failure(c, 500, errors.New("internal error"))
+			failure(c, err)
 			return
 		}
-		web.Success(c, 200, products)
+		web.Success(c, 200, product)
 	}
 }
 
-// Get obtiene un producto por id con datos de warehouse
-func (h *productHandler) GetFullData() gin.HandlerFunc {
+// GetByCodes obtiene los productos de varios code_value enviados en el body como
+// {"codes": [...]}, los codigos que no existen vuelven en missing. Solo lee datos y
+// por eso no pide token
+func (h *productHandler) GetByCodes() gin.HandlerFunc {
+	type request struct {
+		Codes []string `json:"codes"`
+	}
 	return func(c *gin.Context) {
-		idParam := c.Param("id")
-		id, err := strconv.Atoi(idParam)
-		if err != nil {
-			web.Failure(c, 400, errors.New("invalid id"))
+		var req request
+		if err := c.ShouldBindJSON(&req); err != nil {
+			bindFailure(c)
 			return
 		}
-		product, err := h.s.GetFullData(id)
+		lookup, err := h.s.GetByCodes(c.Request.Context(), req.Codes)
 		if err != nil {
-			web.Failure(c, 404, errors.New("product not found"))
+			failure(c, err)
 			return
 		}
-		web.Success(c, 200, product)
+		web.Success(c, 200, lookup)
 	}
 }
 
-// validateEmptys valida que los campos no esten vacios
-func validateEmptys(product *domain.Product) (bool, error) {
-	switch {
-	case product.Name == "" || product.CodeValue == "" || product.Expiration == "":
-		return false, errors.New("fields can't be empty")
-	case product.Quantity <= 0 || product.Price <= 0:
-		if product.Quantity <= 0 {
-			return false, errors.New("quantity must be greater than 0")
-		}
-		if product.Price <= 0 {
-			return false, errors.New("price must be greater than 0")
+// GetAll obtiene una pagina de productos, con los filtros y el orden del query string
+func (h *productHandler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		q, err := productListQuery(c, h.dates)
+		if err != nil {
+			queryFailure(c, err)
+			return
+		}
+		page, err := h.s.List(c.Request.Context(), q)
+		if err != nil {
+			failure(c, err)
+			return
 		}
+		web.SuccessPage(c, 200, page.Products, productPagination(c, q, page))
 	}
-	return true, nil
 }
 
-// validateExpiration valida que la fecha de expiracion sea valida
-func validateExpiration(exp string) (bool, error) {
-	dates := strings.Split(exp, "/")
-	list := []int{}
-	if len(dates) != 3 {
-		return false, errors.New("invalid expiration date, must be in format: dd/mm/yyyy")
-	}
-	for value := range dates {
-		number, err := strconv.Atoi(dates[value])
+// Search busca productos por nombre con el texto del parametro q, los resultados
+// vienen ordenados por relevancia
+func (h *productHandler) Search() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		q, err := productSearchQuery(c)
 		if err != nil {
-			return false, errors.New("invalid expiration date, must be numbers")
+			queryFailure(c, err)
+			return
+		}
+		hits, err := h.s.Search(c.Request.Context(), q)
+		if err != nil {
+			failure(c, err)
+			return
 		}
-		list = append(list, number)
+		web.Success(c, 200, hits)
 	}
-	condition := (list[0] < 1 || list[0] > 31) && (list[1] < 1 || list[1] > 12) && (list[2] < 1 || list[2] > 9999)
-	if condition {
-		return false, errors.New("invalid expiration date, date must be between 1 and 31/12/9999")
+}
+
+// Get obtiene un producto por id con datos de warehouse
+func (h *productHandler) GetFullData() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.FailureCode(c, 400, "invalid_id", errors.New("invalid id"))
+			return
+		}
+		product, err := h.s.GetFullData(c.Request.Context(), id)
+		if err != nil {
+			failure(c, err)
+			return
+		}
+		web.Success(c, 200, product)
 	}
-	return true, nil
 }
 
 // Post crea un nuevo producto
 func (h *productHandler) Post() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var product domain.Product
+		var request domain.ProductRequest
 		token := c.GetHeader("TOKEN")
 		if token == "" {
-			web.Failure(c, 401, errors.New("token not found"))
+			web.FailureCode(c, 401, "token_not_found", errors.New("token not found"))
 			return
 		}
-		if token != os.Getenv("TOKEN") {
-			web.Failure(c, 401, errors.New("invalid token"))
+		if token != h.token {
+			web.FailureCode(c, 401, "invalid_token", errors.New("invalid token"))
 			return
 		}
-		err := c.ShouldBindJSON(&product)
+		err := c.ShouldBindJSON(&request)
 		if err != nil {
-			web.Failure(c, 400, errors.New("invalid json"))
+			bindFailure(c)
 			return
 		}
-		valid, err := validateEmptys(&product)
-		if !valid {
-			web.Failure(c, 400, err)
-			return
-		}
-		valid, err = validateExpiration(product.Expiration)
-		if !valid {
-			web.Failure(c, 400, err)
+		product, err := request.Validate(h.dates)
+		if err != nil {
+			failure(c, err)
 			return
 		}
-		p, err := h.s.Create(product)
+		p, err := h.s.Create(c.Request.Context(), product)
 		if err != nil {
-			web.Failure(c, 400, err)
+			failure(c, err)
 			return
 		}
 		web.Success(c, 201, p)
@@ -150,22 +169,22 @@ func (h *productHandler) Delete() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader("TOKEN")
 		if token == "" {
-			web.Failure(c, 401, errors.New("token not found"))
+			web.FailureCode(c, 401, "token_not_found", errors.New("token not found"))
 			return
 		}
-		if token != os.Getenv("TOKEN") {
-			web.Failure(c, 401, errors.New("invalid token"))
+		if token != h.token {
+			web.FailureCode(c, 401, "invalid_token", errors.New("invalid token"))
 			return
 		}
 		idParam := c.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
-			web.Failure(c, 400, errors.New("invalid id"))
+			web.FailureCode(c, 400, "invalid_id", errors.New("invalid id"))
 			return
 		}
-		err = h.s.Delete(id)
+		err = h.s.Delete(c.Request.Context(), id)
 		if err != nil {
-			web.Failure(c, 404, err)
+			failure(c, err)
 			return
 		}
 		web.Success(c, 204, nil)
@@ -177,47 +196,38 @@ func (h *productHandler) Put() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader("TOKEN")
 		if token == "" {
-			web.Failure(c, 401, errors.New("token not found"))
+			web.FailureCode(c, 401, "token_not_found", errors.New("token not found"))
 			return
 		}
-		if token != os.Getenv("TOKEN") {
-			web.Failure(c, 401, errors.New("invalid token"))
+		if token != h.token {
+			web.FailureCode(c, 401, "invalid_token", errors.New("invalid token"))
 			return
 		}
 		idParam := c.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
-			web.Failure(c, 400, errors.New("invalid id"))
+			web.FailureCode(c, 400, "invalid_id", errors.New("invalid id"))
 			return
 		}
-		_, err = h.s.GetByID(id)
+		_, err = h.s.GetByID(c.Request.Context(), id)
 		if err != nil {
-			web.Failure(c, 404, errors.New("product not found"))
+			failure(c, err)
 			return
 		}
+		var request domain.ProductRequest
+		err = c.ShouldBindJSON(&request)
 		if err != nil {
-			web.Failure(c, 409, err)
+			bindFailure(c)
 			return
 		}
-		var product domain.Product
-		err = c.ShouldBindJSON(&product)
+		product, err := request.Validate(h.dates)
 		if err != nil {
-			web.Failure(c, 400, errors.New("invalid json"))
+			failure(c, err)
 			return
 		}
-		valid, err := validateEmptys(&product)
-		if !valid {
-			web.Failure(c, 400, err)
-			return
-		}
-		valid, err = validateExpiration(product.Expiration)
-		if !valid {
-			web.Failure(c, 400, err)
-			return
-		}
-		p, err := h.s.Update(id, product)
+		p, err := h.s.Update(c.Request.Context(), id, product)
 		if err != nil {
-			web.Failure(c, 409, err)
+			failure(c, err)
 			return
 		}
 		web.Success(c, 200, p)
@@ -226,58 +236,40 @@ func (h *productHandler) Put() gin.HandlerFunc {
 
 // Patch actualiza un producto o alguno de sus campos
 func (h *productHandler) Patch() gin.HandlerFunc {
-	type Request struct {
-		Name        string  `json:"name,omitempty"`
-		Quantity    int     `json:"quantity,omitempty"`
-		CodeValue   string  `json:"code_value,omitempty"`
-		IsPublished bool    `json:"is_published,omitempty"`
-		Expiration  string  `json:"expiration,omitempty"`
-		Price       float64 `json:"price,omitempty"`
-	}
 	return func(c *gin.Context) {
 		token := c.GetHeader("TOKEN")
 		if token == "" {
-			web.Failure(c, 401, errors.New("token not found"))
+			web.FailureCode(c, 401, "token_not_found", errors.New("token not found"))
 			return
 		}
-		if token != os.Getenv("TOKEN") {
-			web.Failure(c, 401, errors.New("invalid token"))
+		if token != h.token {
+			web.FailureCode(c, 401, "invalid_token", errors.New("invalid token"))
 			return
 		}
-		var r Request
+		var r domain.ProductRequest
 		idParam := c.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
-			web.Failure(c, 400, errors.New("invalid id"))
+			web.FailureCode(c, 400, "invalid_id", errors.New("invalid id"))
 			return
 		}
-		_, err = h.s.GetByID(id)
+		_, err = h.s.GetByID(c.Request.Context(), id)
 		if err != nil {
-			web.Failure(c, 404, errors.New("product not found"))
+			failure(c, err)
 			return
 		}
 		if err := c.ShouldBindJSON(&r); err != nil {
-			web.Failure(c, 400, errors.New("invalid json"))
+			bindFailure(c)
 			return
 		}
-		update := domain.Product{
-			Name:        r.Name,
-			Quantity:    r.Quantity,
-			CodeValue:   r.CodeValue,
-			IsPublished: r.IsPublished,
-			Expiration:  r.Expiration,
-			Price:       r.Price,
-		}
-		if update.Expiration != "" {
-			valid, err := validateExpiration(update.Expiration)
-			if !valid {
-				web.Failure(c, 400, err)
-				return
-			}
+		update, err := r.ValidatePatch(h.dates)
+		if err != nil {
+			failure(c, err)
+			return
 		}
-		p, err := h.s.Update(id, update)
+		p, err := h.s.Update(c.Request.Context(), id, update)
 		if err != nil {
-			web.Failure(c, 409, err)
+			failure(c, err)
 			return
 		}
 		web.Success(c, 200, p)
@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/product"
+	"github.com/bootcamp-go/consignas-go-db.git/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// pageBody es la respuesta de un listado
+type pageBody struct {
+	Data       []domain.Product `json:"data"`
+	Pagination web.Pagination   `json:"pagination"`
+}
+
+func TestProductHandler_GetAll(t *testing.T) {
+	products := []domain.Product{{Id: 3, Name: "Oil"}, {Id: 7, Name: "Wine"}}
+
+	t.Run("Success, filters and sort", func(t *testing.T) {
+		// arrange
+		published, minPrice, maxQuantity := true, 10.5, 50
+		service := &stubService{page: product.Page{Products: products, Total: 9, HasNext: true, HasPrev: true}}
+		r := gin.New()
+		r.GET("/products", NewProductHandler(service, "token", domain.NewDateFormats(true)).GetAll())
+		req := httptest.NewRequest(http.MethodGet, "/products?limit=2&offset=4&sort=-price,name&is_published=true&id_warehouse=1&price_min=10.5&quantity_max=50&expiration_after=2022-01-01", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		var body pageBody
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, product.ListQuery{
+			Filter: product.Filter{IsPublished: &published, WarehouseId: 1, MinPrice: &minPrice, MaxQuantity: &maxQuantity, ExpiresAfter: domain.NewDate(2022, 1, 1)},
+			Sort:   []product.SortField{{Field: "price", Desc: true}, {Field: "name"}},
+			Limit:  2,
+			Offset: 4,
+		}, service.query)
+		assert.Equal(t, products, body.Data)
+		assert.Equal(t, 9, body.Pagination.Total)
+		assert.Equal(t, "/products?expiration_after=2022-01-01&id_warehouse=1&is_published=true&limit=2&offset=6&price_min=10.5&quantity_max=50&sort=-price%2Cname", body.Pagination.Next)
+		assert.Equal(t, "/products?expiration_after=2022-01-01&id_warehouse=1&is_published=true&limit=2&offset=2&price_min=10.5&quantity_max=50&sort=-price%2Cname", body.Pagination.Prev)
+	})
+
+	t.Run("Success, cursor links", func(t *testing.T) {
+		// arrange
+		service := &stubService{page: product.Page{Products: products, Total: 9, HasNext: true, HasPrev: true}}
+		r := gin.New()
+		r.GET("/products", NewProductHandler(service, "token", domain.NewDateFormats(true)).GetAll())
+		req := httptest.NewRequest(http.MethodGet, "/products?after=1", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		var body pageBody
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, product.ListQuery{Limit: product.DefaultLimit, After: 1}, service.query)
+		assert.Equal(t, "/products?after=7", body.Pagination.Next)
+		assert.Equal(t, "/products?before=3", body.Pagination.Prev)
+	})
+
+	t.Run("failed, parameter is not a number", func(t *testing.T) {
+		// arrange
+		r := gin.New()
+		r.GET("/products", NewProductHandler(&stubService{}, "token", domain.NewDateFormats(true)).GetAll())
+		req := httptest.NewRequest(http.MethodGet, "/products?price_min=cheap", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		var problem web.Problem
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "invalid_query", problem.Code)
+		assert.Equal(t, []web.FieldError{{Field: "price_min", Message: "must be a number"}}, problem.Errors)
+	})
+
+	t.Run("failed, invalid values", func(t *testing.T) {
+		// arrange
+		r := gin.New()
+		r.GET("/products", NewProductHandler(&stubService{}, "token", domain.NewDateFormats(true)).GetAll())
+		req := httptest.NewRequest(http.MethodGet, "/products?limit=500&sort=color&after=2", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		var problem web.Problem
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "invalid_query", problem.Code)
+		assert.Equal(t, []web.FieldError{
+			{Field: "limit", Message: "must be between 1 and 100"},
+			{Field: "sort", Message: "must be id or -id to use after or before"},
+			{Field: "sort", Message: `can't sort by "color"`},
+		}, problem.Errors)
+	})
+}
+
+func TestProductHandler_Search(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		hits := []product.SearchHit{{Product: domain.Product{Id: 2, Name: "Oil - Margarine"}, Score: 1.5, Highlight: "Oil - <mark>Marg</mark>arine"}}
+		service := &stubService{hits: hits}
+		r := gin.New()
+		r.GET("/products/search", NewProductHandler(service, "token", domain.NewDateFormats(true)).Search())
+		req := httptest.NewRequest(http.MethodGet, "/products/search?q=Marg&limit=5", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		var body struct {
+			Data []product.SearchHit `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, product.SearchQuery{Terms: []string{"marg"}, Limit: 5}, service.search)
+		assert.Equal(t, hits, body.Data)
+		assert.Contains(t, res.Body.String(), `"name":"Oil - Margarine","quantity":0`)
+	})
+
+	t.Run("failed, empty query", func(t *testing.T) {
+		// arrange
+		r := gin.New()
+		r.GET("/products/search", NewProductHandler(&stubService{}, "token", domain.NewDateFormats(true)).Search())
+		req := httptest.NewRequest(http.MethodGet, "/products/search?q=--", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		var problem web.Problem
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "invalid_query", problem.Code)
+		assert.Equal(t, []web.FieldError{{Field: "q", Message: "must have at least one letter or number"}}, problem.Errors)
+	})
+}
+
+// newCodeService crea un servicio en memoria con dos productos, A-1 y B-2
+func newCodeService(t *testing.T) product.Service {
+	db := database.NewMemoryDB()
+	warehouse := db.InsertWarehouse(domain.Warehouse{Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100})
+	for _, code := range []string{"A-1", "B-2"} {
+		_, err := db.InsertProduct(domain.Product{Name: "Product " + code, Quantity: 1, CodeValue: code, Expiration: domain.NewDate(2030, 1, 1), Price: 1, WarehouseId: warehouse.Id})
+		assert.NoError(t, err)
+	}
+	return product.NewService(product.NewMemoryRepository(db))
+}
+
+func TestProductHandler_GetByCode(t *testing.T) {
+	r := gin.New()
+	r.GET("/products/code/:code", NewProductHandler(newCodeService(t), "token", domain.NewDateFormats(true)).GetByCode())
+
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodGet, "/products/code/B-2", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		var body struct {
+			Data domain.Product `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, 2, body.Data.Id)
+		assert.Equal(t, "B-2", body.Data.CodeValue)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodGet, "/products/code/C-3", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		var problem web.Problem
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, "product_not_found", problem.Code)
+	})
+}
+
+func TestProductHandler_GetByCodes(t *testing.T) {
+	r := gin.New()
+	r.POST("/products/codes", NewProductHandler(newCodeService(t), "token", domain.NewDateFormats(true)).GetByCodes())
+
+	t.Run("Success, in the order of the request", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodPost, "/products/codes", strings.NewReader(`{"codes":["B-2","C-3"," A-1","B-2"]}`))
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		var body struct {
+			Data product.CodeLookup `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Len(t, body.Data.Products, 2)
+		assert.Equal(t, "B-2", body.Data.Products[0].CodeValue)
+		assert.Equal(t, "A-1", body.Data.Products[1].CodeValue)
+		assert.Equal(t, []string{"C-3"}, body.Data.Missing)
+	})
+
+	t.Run("Success, nothing missing", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodPost, "/products/codes", strings.NewReader(`{"codes":["A-1"]}`))
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Contains(t, res.Body.String(), `"missing":[]`)
+	})
+
+	t.Run("failed, blank codes", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodPost, "/products/codes", strings.NewReader(`{"codes":["A-1",""]}`))
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		var problem web.Problem
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		assert.Equal(t, "validation_failed", problem.Code)
+		assert.Equal(t, []web.FieldError{{Field: "codes[1]", Message: "can't be blank"}}, problem.Errors)
+	})
+
+	t.Run("failed, invalid json", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodPost, "/products/codes", strings.NewReader(`{"codes":"A-1"}`))
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Contains(t, res.Body.String(), `"code":"invalid_json"`)
+	})
+}
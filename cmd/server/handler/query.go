@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/product"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/warehouse"
+	"github.com/bootcamp-go/consignas-go-db.git/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+// queryParams lee los parametros del query string y junta los problemas de todos
+// los que no se pueden leer
+type queryParams struct {
+	c     *gin.Context
+	v     domain.Validator
+	dates domain.DateFormats
+}
+
+// int lee el parametro name en dst, si no esta dst no cambia
+func (p *queryParams) int(name string, dst *int) {
+	value, ok := p.c.GetQuery(name)
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(value)
+	p.v.Check(err == nil, name, "must be an integer")
+	*dst = n
+}
+
+// intPtr lee el parametro name, nil si no esta
+func (p *queryParams) intPtr(name string) *int {
+	if _, ok := p.c.GetQuery(name); !ok {
+		return nil
+	}
+	var n int
+	p.int(name, &n)
+	return &n
+}
+
+// float lee el parametro name, nil si no esta
+func (p *queryParams) float(name string) *float64 {
+	value, ok := p.c.GetQuery(name)
+	if !ok {
+		return nil
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	p.v.Check(err == nil, name, "must be a number")
+	return &n
+}
+
+// bool lee el parametro name, nil si no esta
+func (p *queryParams) bool(name string) *bool {
+	value, ok := p.c.GetQuery(name)
+	if !ok {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	p.v.Check(err == nil, name, "must be true or false")
+	return &b
+}
+
+// date lee el parametro name, la fecha cero si no esta
+func (p *queryParams) date(name string) domain.Date {
+	value, ok := p.c.GetQuery(name)
+	if !ok {
+		return domain.Date{}
+	}
+	date, err := p.dates.Parse(value)
+	p.v.Check(err == nil, name, "must be a valid date in format yyyy-mm-dd")
+	return date
+}
+
+// productListQuery lee los parametros del listado de productos:
+//
+//	limit, offset, after, before
+//	sort=price,-expiration, el - ordena de mayor a menor
+//	is_published, id_warehouse, price_min, price_max, quantity_min, quantity_max,
+//	expiration_before, expiration_after, con los formatos de dates
+func productListQuery(c *gin.Context, dates domain.DateFormats) (product.ListQuery, error) {
+	p := queryParams{c: c, dates: dates}
+	q := product.ListQuery{Limit: product.DefaultLimit}
+	p.int("limit", &q.Limit)
+	p.int("offset", &q.Offset)
+	p.int("after", &q.After)
+	p.int("before", &q.Before)
+	for _, field := range strings.Split(c.Query("sort"), ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		q.Sort = append(q.Sort, product.SortField{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")})
+	}
+	q.Filter = product.Filter{
+		IsPublished:   p.bool("is_published"),
+		MinPrice:      p.float("price_min"),
+		MaxPrice:      p.float("price_max"),
+		MinQuantity:   p.intPtr("quantity_min"),
+		MaxQuantity:   p.intPtr("quantity_max"),
+		ExpiresBefore: p.date("expiration_before"),
+		ExpiresAfter:  p.date("expiration_after"),
+	}
+	p.int("id_warehouse", &q.Filter.WarehouseId)
+	if err := p.v.Err("invalid query"); err != nil {
+		return product.ListQuery{}, err
+	}
+	return q, q.Validate()
+}
+
+// productSearchQuery lee los parametros de la busqueda de productos, q y limit
+func productSearchQuery(c *gin.Context) (product.SearchQuery, error) {
+	p := queryParams{c: c}
+	limit := product.DefaultLimit
+	p.int("limit", &limit)
+	if err := p.v.Err("invalid query"); err != nil {
+		return product.SearchQuery{}, err
+	}
+	return product.NewSearchQuery(c.Query("q"), limit)
+}
+
+// warehouseDeleteOptions lee los parametros de la eliminacion de un warehouse,
+// products=refuse|cascade|reassign y reassign_to. Sin products se niega a eliminar
+// un warehouse con productos
+func warehouseDeleteOptions(c *gin.Context, id int) (warehouse.DeleteOptions, error) {
+	p := queryParams{c: c}
+	opts := warehouse.DeleteOptions{Policy: warehouse.DeletePolicy(c.DefaultQuery("products", string(warehouse.DeleteRefuse)))}
+	p.int("reassign_to", &opts.ReassignTo)
+	if err := p.v.Err("invalid query"); err != nil {
+		return warehouse.DeleteOptions{}, err
+	}
+	return opts, opts.Validate(id)
+}
+
+// productPagination arma la paginacion de la respuesta del listado. Las paginas
+// ordenadas por id siguen con los cursores after y before, las demas con offset
+func productPagination(c *gin.Context, q product.ListQuery, page product.Page) web.Pagination {
+	pagination := web.Pagination{Total: page.Total, Limit: q.Limit, Offset: q.Offset}
+	if q.Keyset() {
+		if len(page.Products) == 0 {
+			return pagination
+		}
+		if page.HasNext {
+			last := page.Products[len(page.Products)-1].Id
+			pagination.Next = web.PageURL(c, map[string]string{"after": strconv.Itoa(last), "before": ""})
+		}
+		if page.HasPrev {
+			first := page.Products[0].Id
+			pagination.Prev = web.PageURL(c, map[string]string{"before": strconv.Itoa(first), "after": ""})
+		}
+		return pagination
+	}
+	if page.HasNext {
+		pagination.Next = web.PageURL(c, map[string]string{"offset": strconv.Itoa(q.Offset + q.Limit)})
+	}
+	if page.HasPrev {
+		offset := ""
+		if q.Offset > q.Limit {
+			offset = strconv.Itoa(q.Offset - q.Limit)
+		}
+		pagination.Prev = web.PageURL(c, map[string]string{"offset": offset})
+	}
+	return pagination
+}
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"errors"
-	"os"
 	"strconv"
 
 	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
@@ -12,12 +11,14 @@ import (
 )
 
 type warehouseHandler struct {
-	w warehouse.Service
+	w     warehouse.Service
+	token string
 }
 
-func NewWarehouseHandler(w warehouse.Service) *warehouseHandler {
+func NewWarehouseHandler(w warehouse.Service, token string) *warehouseHandler {
 	return &warehouseHandler{
-		w: w,
+		w:     w,
+		token: token,
 	}
 }
 
@@ -26,53 +27,42 @@ func (h *warehouseHandler) GetByID() gin.HandlerFunc {
 		idParam := c.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
-			web.Failure(c, 400, errors.New("invalid id"))
+			web.FailureCode(c, 400, "invalid_id", errors.New("invalid id"))
 			return
 		}
-		warehouse, err := h.w.GetByID(id)
+		warehouse, err := h.w.GetByID(c.Request.Context(), id)
 		if err != nil {
-			web.Failure(c, 404, errors.New("warehouse not found"))
+			failure(c, err)
 			return
 		}
 		web.Success(c, 200, warehouse)
 	}
 }
 
-func validate(warehouse *domain.Warehouse) (bool, error) {
-	switch {
-	case warehouse.Name == "" || warehouse.Address == "" || warehouse.Telephone == "":
-		return false, errors.New("fields can't be empty")
-	case warehouse.Capacity <= 0:
-		return false, errors.New("capacity must be greater than 0")
-	}
-	return true, nil
-}
-
 func (h *warehouseHandler) Post() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var warehouse domain.Warehouse
 		token := c.GetHeader("TOKEN")
 		if token == "" {
-			web.Failure(c, 401, errors.New("token not found"))
+			web.FailureCode(c, 401, "token_not_found", errors.New("token not found"))
 			return
 		}
-		if token != os.Getenv("TOKEN") {
-			web.Failure(c, 401, errors.New("invalid token"))
+		if token != h.token {
+			web.FailureCode(c, 401, "invalid_token", errors.New("invalid token"))
 			return
 		}
 		err := c.ShouldBindJSON(&warehouse)
 		if err != nil {
-			web.Failure(c, 400, errors.New("invalid json"))
+			web.FailureCode(c, 400, "invalid_json", errors.New("invalid json"))
 			return
 		}
-		valid, err := validate(&warehouse)
-		if !valid {
-			web.Failure(c, 400, err)
+		if err := warehouse.Validate(); err != nil {
+			failure(c, err)
 			return
 		}
-		w, err := h.w.Create(warehouse)
+		w, err := h.w.Create(c.Request.Context(), warehouse)
 		if err != nil {
-			web.Failure(c, 400, err)
+			failure(c, err)
 			return
 		}
 		web.Success(c, 201, w)
@@ -81,9 +71,9 @@ func (h *warehouseHandler) Post() gin.HandlerFunc {
 
 func (h *warehouseHandler) GetAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		warehouses, err := h.w.GetAll()
+		warehouses, err := h.w.GetAll(c.Request.Context())
 		if err != nil {
-			web.Failure(c, 500, errors.New("internal error"))
+			failure(c, err)
 			return
 		}
 		web.Success(c, 200, warehouses)
@@ -95,14 +85,127 @@ func (h *warehouseHandler) ReportProducts() gin.HandlerFunc {
 		idParam := c.Query("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
-			web.Failure(c, 400, errors.New("invalid id"))
+			web.FailureCode(c, 400, "invalid_id", errors.New("invalid id"))
 			return
 		}
-		warehouse, err := h.w.ReportProducts(id)
+		warehouse, err := h.w.ReportProducts(c.Request.Context(), id)
 		if err != nil {
-			web.Failure(c, 404, errors.New("warehouse not found"))
+			failure(c, err)
 			return
 		}
 		web.Success(c, 200, warehouse)
 	}
 }
+
+// Put reemplaza todos los datos de un warehouse
+func (h *warehouseHandler) Put() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader("TOKEN")
+		if token == "" {
+			web.FailureCode(c, 401, "token_not_found", errors.New("token not found"))
+			return
+		}
+		if token != h.token {
+			web.FailureCode(c, 401, "invalid_token", errors.New("invalid token"))
+			return
+		}
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.FailureCode(c, 400, "invalid_id", errors.New("invalid id"))
+			return
+		}
+		var warehouse domain.Warehouse
+		if err := c.ShouldBindJSON(&warehouse); err != nil {
+			bindFailure(c)
+			return
+		}
+		if err := warehouse.Validate(); err != nil {
+			failure(c, err)
+			return
+		}
+		w, err := h.w.Update(c.Request.Context(), id, warehouse)
+		if err != nil {
+			failure(c, err)
+			return
+		}
+		web.Success(c, 200, w)
+	}
+}
+
+// Patch actualiza los datos enviados de un warehouse
+func (h *warehouseHandler) Patch() gin.HandlerFunc {
+	type Request struct {
+		Name      string `json:"name,omitempty"`
+		Address   string `json:"address,omitempty"`
+		Telephone string `json:"telephone,omitempty"`
+		Capacity  int    `json:"capacity,omitempty"`
+	}
+	return func(c *gin.Context) {
+		token := c.GetHeader("TOKEN")
+		if token == "" {
+			web.FailureCode(c, 401, "token_not_found", errors.New("token not found"))
+			return
+		}
+		if token != h.token {
+			web.FailureCode(c, 401, "invalid_token", errors.New("invalid token"))
+			return
+		}
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.FailureCode(c, 400, "invalid_id", errors.New("invalid id"))
+			return
+		}
+		var r Request
+		if err := c.ShouldBindJSON(&r); err != nil {
+			bindFailure(c)
+			return
+		}
+		update := domain.Warehouse{
+			Name:      r.Name,
+			Address:   r.Address,
+			Telephone: r.Telephone,
+			Capacity:  r.Capacity,
+		}
+		if err := update.ValidatePatch(); err != nil {
+			failure(c, err)
+			return
+		}
+		w, err := h.w.Update(c.Request.Context(), id, update)
+		if err != nil {
+			failure(c, err)
+			return
+		}
+		web.Success(c, 200, w)
+	}
+}
+
+// Delete elimina un warehouse, el query string indica que hacer con sus productos
+func (h *warehouseHandler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader("TOKEN")
+		if token == "" {
+			web.FailureCode(c, 401, "token_not_found", errors.New("token not found"))
+			return
+		}
+		if token != h.token {
+			web.FailureCode(c, 401, "invalid_token", errors.New("invalid token"))
+			return
+		}
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.FailureCode(c, 400, "invalid_id", errors.New("invalid id"))
+			return
+		}
+		opts, err := warehouseDeleteOptions(c, id)
+		if err != nil {
+			queryFailure(c, err)
+			return
+		}
+		result, err := h.w.Delete(c.Request.Context(), id, opts)
+		if err != nil {
+			failure(c, err)
+			return
+		}
+		web.Success(c, 200, result)
+	}
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/warehouse"
+	"github.com/bootcamp-go/consignas-go-db.git/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newWarehouseRouter crea un router con los endpoints de warehouses sobre una base en
+// memoria con dos warehouses, el 1 con un producto y el 2 vacio
+func newWarehouseRouter(t *testing.T) (*gin.Engine, *database.MemoryDB) {
+	db := database.NewMemoryDB()
+	mainWarehouse := db.InsertWarehouse(domain.Warehouse{Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100})
+	db.InsertWarehouse(domain.Warehouse{Name: "SuperMarket", Address: "123 Main Street", Telephone: "555-555-5555", Capacity: 2222})
+	_, err := db.InsertProduct(domain.Product{Name: "Oil - Margarine", Quantity: 439, CodeValue: "S82254D", IsPublished: true, Expiration: domain.NewDate(2021, 12, 15), Price: 71.42, WarehouseId: mainWarehouse.Id})
+	assert.NoError(t, err)
+
+	h := NewWarehouseHandler(warehouse.NewService(warehouse.NewMemoryRepository(db)), "token")
+	r := gin.New()
+	r.PUT("/warehouses/:id", h.Put())
+	r.PATCH("/warehouses/:id", h.Patch())
+	r.DELETE("/warehouses/:id", h.Delete())
+	return r, db
+}
+
+// serveWarehouse ejecuta el request con el token valido
+func serveWarehouse(r *gin.Engine, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("TOKEN", "token")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	return res
+}
+
+func TestWarehouseHandler_Put(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		r, db := newWarehouseRouter(t)
+		exp := domain.Warehouse{Id: 2, Name: "SuperMarket Norte", Address: "742 Evergreen Terrace", Telephone: "5551234", Capacity: 300}
+
+		// act
+		res := serveWarehouse(r, http.MethodPut, "/warehouses/2", `{"name":"SuperMarket Norte","address":"742 Evergreen Terrace","telephone":"5551234","capacity":300}`)
+
+		// assert
+		assert.Equal(t, http.StatusOK, res.Code)
+		stored, err := db.Warehouse(2)
+		assert.NoError(t, err)
+		assert.Equal(t, exp, stored)
+	})
+
+	t.Run("failed, every field is required", func(t *testing.T) {
+		// arrange
+		r, _ := newWarehouseRouter(t)
+
+		// act
+		res := serveWarehouse(r, http.MethodPut, "/warehouses/2", `{"name":"SuperMarket Norte"}`)
+
+		// assert
+		var problem web.Problem
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		assert.Len(t, problem.Errors, 3)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		r, _ := newWarehouseRouter(t)
+
+		// act
+		res := serveWarehouse(r, http.MethodPut, "/warehouses/9", `{"name":"SuperMarket Norte","address":"742 Evergreen Terrace","telephone":"5551234","capacity":300}`)
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Contains(t, res.Body.String(), `"code":"warehouse_not_found"`)
+	})
+}
+
+func TestWarehouseHandler_Patch(t *testing.T) {
+	t.Run("Success, only the sent fields", func(t *testing.T) {
+		// arrange
+		r, _ := newWarehouseRouter(t)
+
+		// act
+		res := serveWarehouse(r, http.MethodPatch, "/warehouses/1", `{"address":"742 Evergreen Terrace"}`)
+
+		// assert
+		var body struct {
+			Data domain.Warehouse `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, domain.Warehouse{Id: 1, Name: "Main Warehouse", Address: "742 Evergreen Terrace", Telephone: "4555666", Capacity: 100}, body.Data)
+	})
+
+	t.Run("failed, blank name", func(t *testing.T) {
+		// arrange
+		r, _ := newWarehouseRouter(t)
+
+		// act
+		res := serveWarehouse(r, http.MethodPatch, "/warehouses/1", `{"name":"  "}`)
+
+		// assert
+		assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		assert.Contains(t, res.Body.String(), `{"field":"name","message":"can't be empty"}`)
+	})
+}
+
+func TestWarehouseHandler_Delete(t *testing.T) {
+	t.Run("Success, refuse by default without products", func(t *testing.T) {
+		// arrange
+		r, _ := newWarehouseRouter(t)
+
+		// act
+		res := serveWarehouse(r, http.MethodDelete, "/warehouses/2", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.JSONEq(t, `{"data":{"products":"refuse","affected_products":0}}`, res.Body.String())
+	})
+
+	t.Run("Success, reassign", func(t *testing.T) {
+		// arrange
+		r, db := newWarehouseRouter(t)
+
+		// act
+		res := serveWarehouse(r, http.MethodDelete, "/warehouses/1?products=reassign&reassign_to=2", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.JSONEq(t, `{"data":{"products":"reassign","affected_products":1}}`, res.Body.String())
+		assert.Equal(t, 1, db.CountProducts(2))
+	})
+
+	t.Run("failed, refuse with products", func(t *testing.T) {
+		// arrange
+		r, db := newWarehouseRouter(t)
+
+		// act
+		res := serveWarehouse(r, http.MethodDelete, "/warehouses/1", "")
+
+		// assert
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Contains(t, res.Body.String(), `"code":"warehouse_has_products"`)
+		_, err := db.Warehouse(1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed, invalid options", func(t *testing.T) {
+		// arrange
+		r, _ := newWarehouseRouter(t)
+
+		// act
+		res := serveWarehouse(r, http.MethodDelete, "/warehouses/1?products=move&reassign_to=x", "")
+
+		// assert
+		var problem web.Problem
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "invalid_query", problem.Code)
+		assert.Equal(t, []web.FieldError{{Field: "reassign_to", Message: "must be an integer"}}, problem.Errors)
+	})
+
+	t.Run("failed, reassign to itself", func(t *testing.T) {
+		// arrange
+		r, _ := newWarehouseRouter(t)
+
+		// act
+		res := serveWarehouse(r, http.MethodDelete, "/warehouses/1?products=reassign&reassign_to=1", "")
+
+		// assert
+		var problem web.Problem
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, []web.FieldError{{Field: "reassign_to", Message: "can't be the deleted warehouse"}}, problem.Errors)
+	})
+
+	t.Run("failed, reassign to a missing warehouse", func(t *testing.T) {
+		// arrange
+		r, _ := newWarehouseRouter(t)
+
+		// act
+		res := serveWarehouse(r, http.MethodDelete, "/warehouses/1?products=reassign&reassign_to=9", "")
+
+		// assert
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Contains(t, res.Body.String(), `"code":"reference_not_found"`)
+	})
+}
@@ -1,68 +1,200 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"flag"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/bootcamp-go/consignas-go-db.git/cmd/server/handler"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/config"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/health"
 	"github.com/bootcamp-go/consignas-go-db.git/internal/product"
 	"github.com/bootcamp-go/consignas-go-db.git/internal/warehouse"
+	"github.com/bootcamp-go/consignas-go-db.git/pkg/store"
+	"github.com/bootcamp-go/consignas-go-db.git/pkg/web"
 	"github.com/gin-gonic/gin"
-	"github.com/go-sql-driver/mysql"
+	_ "github.com/go-sql-driver/mysql"
 )
 
+// readyTimeout es el tiempo que tiene cada check de /readyz para responder
+const readyTimeout = 2 * time.Second
+
 func main() {
+	configPath := flag.String("config", "", "yaml or json config file, the env vars override its values")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	timeouts := cfg.Timeouts()
 
-	// storage := store.NewJsonStore("./products.json")
-	// repo := product.NewRepository(storage)
+	// ctx se cancela con SIGINT o SIGTERM, corta la espera de la base y apaga el servidor
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	databaseConfig := mysql.Config{
-		User:      "root",
-		Addr:      "localhost:3306",
-		DBName:    "my_db",
-		ParseTime: true,
+	// conn agrega el log de consultas lentas a la base cuando esta habilitado
+	conn := func(db *sql.DB) database.Conn {
+		if cfg.Server.SlowQuery <= 0 {
+			return db
+		}
+		return database.NewSlowQueryLogger(db, time.Duration(cfg.Server.SlowQuery), nil)
 	}
-	database, err := sql.Open("mysql", databaseConfig.FormatDSN())
-	if err != nil {
-		panic(err)
+
+	var repository product.Repository
+	var warehouseRepository warehouse.Repository
+	var sqlDatabase *sql.DB
+	switch cfg.Storage.Backend {
+	case config.StorageMySQL:
+		db, err := sql.Open("mysql", cfg.MySQLDSN())
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+		cfg.Database.Pool.Apply(db)
+		sqlDatabase = db
+		repository = product.NewMySQLRepository(conn(db))
+		warehouseRepository = warehouse.NewMySQLRepository(conn(db))
+	case config.StorageSQLite:
+		db, err := database.OpenSQLite(cfg.Database.SQLite.Path)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+		cfg.Database.Pool.Apply(db)
+		sqlDatabase = db
+		repository = product.NewSQLiteRepository(conn(db))
+		warehouseRepository = warehouse.NewSQLiteRepository(conn(db))
+	case config.StoragePostgres:
+		db, err := database.OpenPostgres(cfg.Database.Postgres.DSN)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+		cfg.Database.Pool.Apply(db)
+		sqlDatabase = db
+		repository = product.NewPostgresRepository(conn(db))
+		warehouseRepository = warehouse.NewPostgresRepository(conn(db))
+	case config.StorageMemory:
+		db := database.NewMemoryDB()
+		repository = product.NewMemoryRepository(db)
+		warehouseRepository = warehouse.NewMemoryRepository(db)
+	case config.StorageJSON:
+		var storage store.StoreInterface
+		if cfg.Database.JSON.Journal {
+			storage = store.NewLogStore(cfg.Database.JSON.Products, cfg.Database.JSON.Warehouses, store.DefaultCompactEvery)
+		} else {
+			storage = store.NewJsonStore(cfg.Database.JSON.Products, cfg.Database.JSON.Warehouses)
+		}
+		// con el journal Close compacta los cambios en el archivo de productos al apagar
+		defer storage.Close()
+		repository = product.NewRepository(storage)
+		warehouseRepository = warehouse.NewRepository(storage)
+	}
+	if sqlDatabase != nil {
+		// la base puede levantar despues que el servidor, se reintenta hasta ConnectTimeout
+		waitCtx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Database.ConnectTimeout))
+		err := database.Wait(waitCtx, sqlDatabase, nil)
+		cancel()
+		if err != nil {
+			panic(err)
+		}
 	}
-	defer database.Close()
-	if err = database.Ping(); err != nil {
-		panic(err)
+	if cfg.Storage.Migrate && sqlDatabase != nil {
+		if err := database.Migrate(sqlDatabase, database.Dialect(cfg.Storage.Backend)); err != nil {
+			panic(err)
+		}
+		log.Println("migrations applied")
 	}
 	log.Println("database Configured")
 
-	repository := product.NewMySQLRepository(database)
+	checker := health.NewChecker(readyTimeout)
+	if sqlDatabase != nil {
+		migrator, err := database.NewMigrator(sqlDatabase, database.Dialect(cfg.Storage.Backend))
+		if err != nil {
+			panic(err)
+		}
+		checker.Add("database", health.Ping(sqlDatabase))
+		checker.Add("migrations", health.Migrations(migrator))
+		checker.Add("pool", health.Pool(sqlDatabase))
+	}
+	healthHandler := handler.NewHealthHandler(checker)
+
 	service := product.NewService(repository)
-	productHandler := handler.NewProductHandler(service)
+	productHandler := handler.NewProductHandler(service, cfg.Auth.Token, domain.NewDateFormats(cfg.Server.LegacyDates))
 
-	warehouseRepository := warehouse.NewMySQLRepository(database)
 	warehouseService := warehouse.NewService(warehouseRepository)
-	warehouseHandler := handler.NewWarehouseHandler(warehouseService)
+	warehouseHandler := handler.NewWarehouseHandler(warehouseService, cfg.Auth.Token)
 
 	r := gin.Default()
+	r.Use(web.RequestID())
 
 	r.GET("/ping", func(c *gin.Context) { c.String(200, "pong") })
+	r.GET("/healthz", healthHandler.Live())
+	r.GET("/readyz", healthHandler.Ready())
 
 	products := r.Group("/products")
 	{
-		products.GET(":id", productHandler.GetByID())
-		products.GET("", productHandler.GetAll())
-		products.GET("/details/:id", productHandler.GetFullData())
-
-		products.POST("", productHandler.Post())
-		products.DELETE(":id", productHandler.Delete())
-		products.PATCH(":id", productHandler.Patch())
-		products.PUT(":id", productHandler.Put())
+		products.GET(":id", handler.Timeout(timeouts.For("products.get")), productHandler.GetByID())
+		products.GET("", handler.Timeout(timeouts.For("products.list")), productHandler.GetAll())
+		products.GET("/details/:id", handler.Timeout(timeouts.For("products.details")), productHandler.GetFullData())
+		products.GET("/search", handler.Timeout(timeouts.For("products.search")), productHandler.Search())
+		products.GET("/code/:code", handler.Timeout(timeouts.For("products.code")), productHandler.GetByCode())
+		products.POST("/codes", handler.Timeout(timeouts.For("products.codes")), productHandler.GetByCodes())
+
+		products.POST("", handler.Timeout(timeouts.For("products.create")), productHandler.Post())
+		products.DELETE(":id", handler.Timeout(timeouts.For("products.delete")), productHandler.Delete())
+		products.PATCH(":id", handler.Timeout(timeouts.For("products.patch")), productHandler.Patch())
+		products.PUT(":id", handler.Timeout(timeouts.For("products.update")), productHandler.Put())
 	}
 
 	warehouses := r.Group("/warehouses")
 	{
-		warehouses.GET("", warehouseHandler.GetAll())
-		warehouses.GET("/:id", warehouseHandler.GetByID())
-		warehouses.GET("/reportProducts", warehouseHandler.ReportProducts())
-		warehouses.POST("", warehouseHandler.Post())
+		warehouses.GET("", handler.Timeout(timeouts.For("warehouses.list")), warehouseHandler.GetAll())
+		warehouses.GET("/:id", handler.Timeout(timeouts.For("warehouses.get")), warehouseHandler.GetByID())
+		warehouses.GET("/reportProducts", handler.Timeout(timeouts.For("warehouses.report")), warehouseHandler.ReportProducts())
+		warehouses.POST("", handler.Timeout(timeouts.For("warehouses.create")), warehouseHandler.Post())
+		warehouses.PUT("/:id", handler.Timeout(timeouts.For("warehouses.update")), warehouseHandler.Put())
+		warehouses.PATCH("/:id", handler.Timeout(timeouts.For("warehouses.patch")), warehouseHandler.Patch())
+		warehouses.DELETE("/:id", handler.Timeout(timeouts.For("warehouses.delete")), warehouseHandler.Delete())
 	}
 
-	r.Run(":8080")
+	server := &http.Server{Addr: cfg.Server.Addr, Handler: r}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+	log.Printf("listening on %s", cfg.Server.Addr)
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+		return
+	case <-ctx.Done():
+	}
+	stop()
+
+	// se dejan de aceptar conexiones y se espera a los requests en curso, los defer
+	// cierran el pool de la base al salir
+	log.Println("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout))
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("shutdown: %v", err)
+	}
+	log.Println("server stopped")
 }
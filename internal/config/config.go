@@ -0,0 +1,391 @@
+// Package config loads the settings of the server and the admin command from a YAML
+// or JSON file, applies the environment variable overrides and validates them
+package config
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/go-sql-driver/mysql"
+	"gopkg.in/yaml.v3"
+)
+
+// Storage backends
+const (
+	StorageMySQL    = "mysql"
+	StorageSQLite   = "sqlite"
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+	StorageJSON     = "json"
+)
+
+// Operations are the endpoints whose query deadline server.query_timeouts can set,
+// the same names cmd/server passes to Timeouts().For
+var Operations = []string{
+	"products.get", "products.list", "products.details", "products.search", "products.code", "products.codes",
+	"products.create", "products.delete", "products.patch", "products.update",
+	"warehouses.list", "warehouses.get", "warehouses.report", "warehouses.create", "warehouses.update",
+	"warehouses.patch", "warehouses.delete",
+}
+
+// Duration is a time.Duration written as "5s" or "1m30s" in the config files
+type Duration time.Duration
+
+// UnmarshalText parses a duration like "5s"
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// MarshalText writes the duration like "5s"
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Config holds every setting of the server
+type Config struct {
+	Server   Server   `json:"server" yaml:"server"`
+	Storage  Storage  `json:"storage" yaml:"storage"`
+	Database Database `json:"database" yaml:"database"`
+	Auth     Auth     `json:"auth" yaml:"auth"`
+}
+
+// Server holds the http settings and the query deadlines of the endpoints
+type Server struct {
+	Addr            string              `json:"addr" yaml:"addr"`
+	ShutdownTimeout Duration            `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	QueryTimeout    Duration            `json:"query_timeout" yaml:"query_timeout"`
+	QueryTimeouts   map[string]Duration `json:"query_timeouts" yaml:"query_timeouts"`
+	SlowQuery       Duration            `json:"slow_query" yaml:"slow_query"`
+	// LegacyDates accepts dd/mm/yyyy dates in the requests besides yyyy-mm-dd
+	LegacyDates bool `json:"legacy_dates" yaml:"legacy_dates"`
+}
+
+// Storage selects the backend and whether the migrations run on startup
+type Storage struct {
+	Backend string `json:"backend" yaml:"backend"`
+	Migrate bool   `json:"migrate" yaml:"migrate"`
+}
+
+// Database holds the connection of each sql backend, how long to wait for it on
+// startup and the pool settings
+type Database struct {
+	MySQL          MySQL    `json:"mysql" yaml:"mysql"`
+	SQLite         SQLite   `json:"sqlite" yaml:"sqlite"`
+	Postgres       Postgres `json:"postgres" yaml:"postgres"`
+	JSON           JSON     `json:"json" yaml:"json"`
+	ConnectTimeout Duration `json:"connect_timeout" yaml:"connect_timeout"`
+	Pool           Pool     `json:"pool" yaml:"pool"`
+}
+
+// MySQL holds the mysql connection
+type MySQL struct {
+	User     string `json:"user" yaml:"user"`
+	Password string `json:"password" yaml:"password"`
+	Addr     string `json:"addr" yaml:"addr"`
+	Name     string `json:"name" yaml:"name"`
+}
+
+// SQLite holds the sqlite database file
+type SQLite struct {
+	Path string `json:"path" yaml:"path"`
+}
+
+// Postgres holds the postgres connection string
+type Postgres struct {
+	DSN string `json:"dsn" yaml:"dsn"`
+}
+
+// JSON holds the files of the json backend, products and warehouses are written
+// under the same file lock. Journal keeps the products in memory and appends each
+// change to products + ".log" instead of rewriting the file, the process then holds
+// the lock until it stops
+type JSON struct {
+	Products   string `json:"products" yaml:"products"`
+	Warehouses string `json:"warehouses" yaml:"warehouses"`
+	Journal    bool   `json:"journal" yaml:"journal"`
+}
+
+// Pool holds the connection pool settings, zero keeps the database/sql default
+type Pool struct {
+	MaxOpenConns    int      `json:"max_open_conns" yaml:"max_open_conns"`
+	MaxIdleConns    int      `json:"max_idle_conns" yaml:"max_idle_conns"`
+	ConnMaxLifetime Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime Duration `json:"conn_max_idle_time" yaml:"conn_max_idle_time"`
+}
+
+// Auth holds the token the write endpoints expect in the TOKEN header
+type Auth struct {
+	Token string `json:"token" yaml:"token"`
+}
+
+// Default returns the settings used when neither the file nor the environment set them
+func Default() Config {
+	return Config{
+		Server: Server{
+			Addr:            ":8080",
+			ShutdownTimeout: Duration(10 * time.Second),
+			QueryTimeout:    Duration(5 * time.Second),
+			QueryTimeouts:   map[string]Duration{"warehouses.report": Duration(30 * time.Second)},
+			SlowQuery:       Duration(200 * time.Millisecond),
+			LegacyDates:     true,
+		},
+		Storage: Storage{Backend: StorageMySQL},
+		Database: Database{
+			MySQL:          MySQL{User: "root", Addr: "localhost:3306", Name: "my_db"},
+			SQLite:         SQLite{Path: "my_db.sqlite"},
+			Postgres:       Postgres{DSN: "postgres://postgres@localhost:5432/my_db?sslmode=disable"},
+			JSON:           JSON{Products: "products.json", Warehouses: "warehouses.json"},
+			ConnectTimeout: Duration(30 * time.Second),
+			Pool:           Pool{MaxOpenConns: 10, MaxIdleConns: 5, ConnMaxLifetime: Duration(5 * time.Minute)},
+		},
+	}
+}
+
+// Load reads the file at path over the defaults and applies the environment
+// overrides, the caller validates the result. The format follows the extension,
+// .json for JSON and anything else for YAML. An empty path skips the file
+func Load(path string) (Config, error) {
+	config := Default()
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			err = json.Unmarshal(content, &config)
+		} else {
+			err = yaml.Unmarshal(content, &config)
+		}
+		if err != nil {
+			return Config{}, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+	if err := config.applyEnv(os.LookupEnv); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// applyEnv overrides the settings with the environment variables that are set
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	texts := map[string]*string{
+		"LISTEN_ADDR":     &c.Server.Addr,
+		"STORAGE":         &c.Storage.Backend,
+		"MYSQL_USER":      &c.Database.MySQL.User,
+		"MYSQL_PASSWORD":  &c.Database.MySQL.Password,
+		"MYSQL_ADDR":      &c.Database.MySQL.Addr,
+		"MYSQL_DATABASE":  &c.Database.MySQL.Name,
+		"SQLITE_PATH":     &c.Database.SQLite.Path,
+		"POSTGRES_DSN":    &c.Database.Postgres.DSN,
+		"JSON_PRODUCTS":   &c.Database.JSON.Products,
+		"JSON_WAREHOUSES": &c.Database.JSON.Warehouses,
+		"TOKEN":           &c.Auth.Token,
+	}
+	for name, field := range texts {
+		if value, ok := lookup(name); ok {
+			*field = value
+		}
+	}
+
+	ints := map[string]*int{
+		"DB_MAX_OPEN_CONNS": &c.Database.Pool.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS": &c.Database.Pool.MaxIdleConns,
+	}
+	for name, field := range ints {
+		if value, ok := lookup(name); ok {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("env %s: %q is not a number", name, value)
+			}
+			*field = number
+		}
+	}
+
+	durations := map[string]*Duration{
+		"SHUTDOWN_TIMEOUT":      &c.Server.ShutdownTimeout,
+		"DB_CONNECT_TIMEOUT":    &c.Database.ConnectTimeout,
+		"QUERY_TIMEOUT":         &c.Server.QueryTimeout,
+		"SLOW_QUERY":            &c.Server.SlowQuery,
+		"DB_CONN_MAX_LIFETIME":  &c.Database.Pool.ConnMaxLifetime,
+		"DB_CONN_MAX_IDLE_TIME": &c.Database.Pool.ConnMaxIdleTime,
+	}
+	for name, field := range durations {
+		if value, ok := lookup(name); ok {
+			if err := field.UnmarshalText([]byte(value)); err != nil {
+				return fmt.Errorf("env %s: %q is not a duration", name, value)
+			}
+		}
+	}
+
+	bools := map[string]*bool{
+		"MIGRATE":      &c.Storage.Migrate,
+		"LEGACY_DATES": &c.Server.LegacyDates,
+		"JSON_JOURNAL": &c.Database.JSON.Journal,
+	}
+	for name, field := range bools {
+		if value, ok := lookup(name); ok {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("env %s: %q is not a boolean", name, value)
+			}
+			*field = parsed
+		}
+	}
+	if value, ok := lookup("QUERY_TIMEOUTS"); ok {
+		timeouts := c.Timeouts()
+		if err := timeouts.Set(value); err != nil {
+			return fmt.Errorf("env QUERY_TIMEOUTS: %w", err)
+		}
+		if c.Server.QueryTimeouts == nil {
+			c.Server.QueryTimeouts = map[string]Duration{}
+		}
+		for operation, timeout := range timeouts.Operations {
+			c.Server.QueryTimeouts[operation] = Duration(timeout)
+		}
+	}
+	return nil
+}
+
+// Validate checks every setting the server uses and reports all the problems at once
+func (c Config) Validate() error {
+	problems := c.databaseProblems()
+	if c.Server.Addr == "" {
+		problems = append(problems, "server.addr is required")
+	}
+	if c.Server.QueryTimeout < 0 || c.Server.SlowQuery < 0 {
+		problems = append(problems, "server.query_timeout and server.slow_query can't be negative")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "server.shutdown_timeout must be greater than 0")
+	}
+	operations := make([]string, 0, len(c.Server.QueryTimeouts))
+	for operation := range c.Server.QueryTimeouts {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+	for _, operation := range operations {
+		if !knownOperation(operation) {
+			problems = append(problems, fmt.Sprintf("server.query_timeouts.%s is not an operation, use one of %s", operation, strings.Join(Operations, ", ")))
+		} else if c.Server.QueryTimeouts[operation] < 0 {
+			problems = append(problems, fmt.Sprintf("server.query_timeouts.%s can't be negative", operation))
+		}
+	}
+	if c.Auth.Token == "" {
+		problems = append(problems, "auth.token is required, set it in the file or with the TOKEN env var")
+	}
+	return invalid(problems)
+}
+
+// knownOperation tells whether operation is in Operations
+func knownOperation(operation string) bool {
+	for _, o := range Operations {
+		if o == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDatabase checks only the storage and database settings, for the commands
+// that connect to the database without serving requests
+func (c Config) ValidateDatabase() error {
+	return invalid(c.databaseProblems())
+}
+
+// databaseProblems returns the problems of the storage and database settings
+func (c Config) databaseProblems() []string {
+	var problems []string
+	switch c.Storage.Backend {
+	case StorageMySQL:
+		if c.Database.MySQL.User == "" || c.Database.MySQL.Addr == "" || c.Database.MySQL.Name == "" {
+			problems = append(problems, "database.mysql user, addr and name are required")
+		}
+	case StorageSQLite:
+		if c.Database.SQLite.Path == "" {
+			problems = append(problems, "database.sqlite.path is required")
+		}
+	case StoragePostgres:
+		if c.Database.Postgres.DSN == "" {
+			problems = append(problems, "database.postgres.dsn is required")
+		}
+	case StorageJSON:
+		if c.Database.JSON.Products == "" || c.Database.JSON.Warehouses == "" {
+			problems = append(problems, "database.json products and warehouses are required")
+		}
+	case StorageMemory:
+	default:
+		problems = append(problems, fmt.Sprintf("storage.backend %q must be mysql, sqlite, postgres, memory or json", c.Storage.Backend))
+	}
+
+	if c.Database.ConnectTimeout <= 0 {
+		problems = append(problems, "database.connect_timeout must be greater than 0")
+	}
+
+	pool := c.Database.Pool
+	if pool.MaxOpenConns < 0 || pool.MaxIdleConns < 0 || pool.ConnMaxLifetime < 0 || pool.ConnMaxIdleTime < 0 {
+		problems = append(problems, "database.pool values can't be negative")
+	}
+	if pool.MaxOpenConns > 0 && pool.MaxIdleConns > pool.MaxOpenConns {
+		problems = append(problems, "database.pool.max_idle_conns can't be greater than max_open_conns")
+	}
+	return problems
+}
+
+// invalid joins the problems in one error, nil when there are none
+func invalid(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("invalid config: " + strings.Join(problems, "; "))
+}
+
+// MySQLDSN returns the mysql connection string
+func (c Config) MySQLDSN() string {
+	config := mysql.NewConfig()
+	config.User = c.Database.MySQL.User
+	config.Passwd = c.Database.MySQL.Password
+	config.Net = "tcp"
+	config.Addr = c.Database.MySQL.Addr
+	config.DBName = c.Database.MySQL.Name
+	config.ParseTime = true
+	return config.FormatDSN()
+}
+
+// Timeouts returns the query deadlines of the endpoints
+func (c Config) Timeouts() database.Timeouts {
+	timeouts := database.Timeouts{Default: time.Duration(c.Server.QueryTimeout), Operations: map[string]time.Duration{}}
+	for operation, timeout := range c.Server.QueryTimeouts {
+		timeouts.Operations[operation] = time.Duration(timeout)
+	}
+	return timeouts
+}
+
+// Apply sets the pool settings that are not zero on database
+func (p Pool) Apply(database *sql.DB) {
+	if p.MaxOpenConns > 0 {
+		database.SetMaxOpenConns(p.MaxOpenConns)
+	}
+	if p.MaxIdleConns > 0 {
+		database.SetMaxIdleConns(p.MaxIdleConns)
+	}
+	if p.ConnMaxLifetime > 0 {
+		database.SetConnMaxLifetime(time.Duration(p.ConnMaxLifetime))
+	}
+	if p.ConnMaxIdleTime > 0 {
+		database.SetConnMaxIdleTime(time.Duration(p.ConnMaxIdleTime))
+	}
+}
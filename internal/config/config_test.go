@@ -0,0 +1,257 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeConfig escribe content en un archivo temporal con el nombre indicado
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// env devuelve un lookup con las variables indicadas
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Success, yaml", func(t *testing.T) {
+		// arrange
+		path := writeConfig(t, "config.yaml", `
+server:
+  addr: ":9090"
+  query_timeouts:
+    products.get: 500ms
+storage:
+  backend: sqlite
+database:
+  sqlite:
+    path: test.sqlite
+  pool:
+    conn_max_idle_time: 1m
+auth:
+  token: "123456"
+`)
+
+		// act
+		cfg, err := Load(path)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, cfg.Validate())
+		assert.Equal(t, ":9090", cfg.Server.Addr)
+		assert.Equal(t, StorageSQLite, cfg.Storage.Backend)
+		assert.Equal(t, "test.sqlite", cfg.Database.SQLite.Path)
+		assert.Equal(t, Duration(time.Minute), cfg.Database.Pool.ConnMaxIdleTime)
+		assert.Equal(t, 10, cfg.Database.Pool.MaxOpenConns)
+		assert.Equal(t, "123456", cfg.Auth.Token)
+		timeouts := cfg.Timeouts()
+		assert.Equal(t, 500*time.Millisecond, timeouts.For("products.get"))
+		assert.Equal(t, 30*time.Second, timeouts.For("warehouses.report"))
+		assert.Equal(t, 5*time.Second, timeouts.For("products.list"))
+	})
+
+	t.Run("Success, json", func(t *testing.T) {
+		// arrange
+		path := writeConfig(t, "config.json", `{"server": {"slow_query": "1s"}, "storage": {"backend": "memory", "migrate": true}}`)
+
+		// act
+		cfg, err := Load(path)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, Duration(time.Second), cfg.Server.SlowQuery)
+		assert.Equal(t, StorageMemory, cfg.Storage.Backend)
+		assert.True(t, cfg.Storage.Migrate)
+	})
+
+	t.Run("Success, json backend", func(t *testing.T) {
+		// arrange
+		path := writeConfig(t, "config.yaml", `
+storage:
+  backend: json
+database:
+  json:
+    products: data/products.json
+auth:
+  token: "123456"
+`)
+
+		// act
+		cfg, err := Load(path)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, cfg.Validate())
+		assert.Equal(t, StorageJSON, cfg.Storage.Backend)
+		assert.Equal(t, JSON{Products: "data/products.json", Warehouses: "warehouses.json"}, cfg.Database.JSON)
+	})
+
+	t.Run("failed, invalid duration", func(t *testing.T) {
+		// arrange
+		path := writeConfig(t, "config.yaml", "server:\n  query_timeout: soon\n")
+
+		// act
+		_, err := Load(path)
+
+		// assert
+		assert.Error(t, err)
+	})
+
+	t.Run("failed, missing file", func(t *testing.T) {
+		// act
+		_, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+
+		// assert
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestConfig_applyEnv(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		cfg := Default()
+
+		// act
+		err := cfg.applyEnv(env(map[string]string{
+			"STORAGE":           "postgres",
+			"POSTGRES_DSN":      "postgres://localhost/test",
+			"TOKEN":             "secret",
+			"DB_MAX_OPEN_CONNS": "20",
+			"QUERY_TIMEOUT":     "2s",
+			"QUERY_TIMEOUTS":    "warehouses.report=1m",
+			"MIGRATE":           "true",
+			"LEGACY_DATES":      "false",
+		}))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, StoragePostgres, cfg.Storage.Backend)
+		assert.Equal(t, "postgres://localhost/test", cfg.Database.Postgres.DSN)
+		assert.Equal(t, "secret", cfg.Auth.Token)
+		assert.Equal(t, 20, cfg.Database.Pool.MaxOpenConns)
+		assert.Equal(t, Duration(2*time.Second), cfg.Server.QueryTimeout)
+		assert.Equal(t, Duration(time.Minute), cfg.Server.QueryTimeouts["warehouses.report"])
+		assert.True(t, cfg.Storage.Migrate)
+		assert.False(t, cfg.Server.LegacyDates)
+	})
+
+	t.Run("failed, invalid number", func(t *testing.T) {
+		// arrange
+		cfg := Default()
+
+		// act
+		err := cfg.applyEnv(env(map[string]string{"DB_MAX_IDLE_CONNS": "many"}))
+
+		// assert
+		assert.EqualError(t, err, `env DB_MAX_IDLE_CONNS: "many" is not a number`)
+	})
+
+	t.Run("failed, invalid boolean", func(t *testing.T) {
+		// arrange
+		cfg := Default()
+
+		// act
+		err := cfg.applyEnv(env(map[string]string{"LEGACY_DATES": "maybe"}))
+
+		// assert
+		assert.EqualError(t, err, `env LEGACY_DATES: "maybe" is not a boolean`)
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("failed, reports every problem", func(t *testing.T) {
+		// arrange
+		cfg := Default()
+		cfg.Server.Addr = ""
+		cfg.Storage.Backend = "oracle"
+		cfg.Database.Pool.MaxIdleConns = 50
+
+		// act
+		err := cfg.Validate()
+
+		// assert
+		assert.EqualError(t, err, `invalid config: storage.backend "oracle" must be mysql, sqlite, postgres, memory or json; `+
+			`database.pool.max_idle_conns can't be greater than max_open_conns; server.addr is required; `+
+			`auth.token is required, set it in the file or with the TOKEN env var`)
+	})
+
+	t.Run("failed, unknown query timeout operation", func(t *testing.T) {
+		// arrange
+		cfg := Default()
+		cfg.Auth.Token = "123456"
+		cfg.Server.QueryTimeouts["products.serch"] = Duration(time.Second)
+		cfg.Server.QueryTimeouts["products.get"] = Duration(-time.Second)
+
+		// act
+		err := cfg.Validate()
+
+		// assert
+		assert.EqualError(t, err, "invalid config: server.query_timeouts.products.get can't be negative; "+
+			"server.query_timeouts.products.serch is not an operation, use one of "+strings.Join(Operations, ", "))
+	})
+
+	t.Run("failed, json backend without files", func(t *testing.T) {
+		// arrange
+		cfg := Default()
+		cfg.Storage.Backend = StorageJSON
+		assert.NoError(t, cfg.applyEnv(env(map[string]string{"JSON_PRODUCTS": "/data/products.json", "JSON_WAREHOUSES": ""})))
+
+		// act
+		err := cfg.ValidateDatabase()
+
+		// assert
+		assert.Equal(t, "/data/products.json", cfg.Database.JSON.Products)
+		assert.EqualError(t, err, "invalid config: database.json products and warehouses are required")
+	})
+
+	t.Run("Success, json journal from env", func(t *testing.T) {
+		// arrange
+		cfg := Default()
+		cfg.Storage.Backend = StorageJSON
+
+		// act
+		errEnv := cfg.applyEnv(env(map[string]string{"JSON_JOURNAL": "true"}))
+		err := cfg.ValidateDatabase()
+
+		// assert
+		assert.NoError(t, errEnv)
+		assert.NoError(t, err)
+		assert.True(t, cfg.Database.JSON.Journal)
+	})
+
+	t.Run("Success, database only", func(t *testing.T) {
+		// arrange
+		cfg := Default()
+
+		// act
+		err := cfg.ValidateDatabase()
+
+		// assert
+		assert.NoError(t, err)
+	})
+}
+
+func TestConfig_MySQLDSN(t *testing.T) {
+	// arrange
+	cfg := Default()
+	cfg.Database.MySQL.Password = "pass"
+
+	// act
+	dsn := cfg.MySQLDSN()
+
+	// assert
+	assert.Equal(t, "root:pass@tcp(localhost:3306)/my_db?parseTime=true", dsn)
+}
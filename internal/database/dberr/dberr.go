@@ -0,0 +1,213 @@
+// Package dberr translates the errors of the sql drivers into the errors the
+// repositories return, so every repository reports a failure the same way
+package dberr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Kinds of database errors, a translated error matches one of them with errors.Is
+var (
+	ErrAccessDenied       = errors.New("access denied for user")
+	ErrNoDatabaseSelected = errors.New("no database selected")
+	ErrNotNullColumn      = errors.New("column cannot be null")
+	ErrUnknownColumn      = errors.New("unknown column")
+	ErrDuplicateEntry     = errors.New("duplicate entry")
+	ErrSyntaxError        = errors.New("syntax error")
+	ErrTableDoesNotExist  = errors.New("table does not exist")
+	ErrForeignKey         = errors.New("referenced row does not exist")
+	ErrDeadlock           = errors.New("deadlock found, retry the transaction")
+	ErrUnavailable        = errors.New("database unavailable")
+	ErrCanceled           = errors.New("request canceled")
+	ErrInternal           = errors.New("internal error")
+)
+
+// Error is a translated database error. Its message is the one of its kind, so it
+// can be shown to clients, and it unwraps to the driver error for the logs
+type Error struct {
+	// Kind is one of the Err variables of this package
+	Kind error
+	// Code is the error code of the driver, empty when the error did not come from it
+	Code string
+	// Cause is the original error
+	Cause error
+}
+
+// Error returns the message of the kind
+func (e *Error) Error() string {
+	return e.Kind.Error()
+}
+
+// Is reports whether target is the kind of the error
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+// Unwrap returns the driver error
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Detail describes the error with its code and the driver message, for the logs
+func (e *Error) Detail() string {
+	detail := e.Kind.Error()
+	if e.Code != "" {
+		detail += " (" + e.Code + ")"
+	}
+	return detail + ": " + e.Cause.Error()
+}
+
+// unavailable reports whether err means that the database could not be reached or
+// did not answer in time
+func unavailable(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) || errors.Is(err, mysql.ErrInvalidConn) || errors.As(err, &netErr)
+}
+
+// interrupted returns the kind of an error that did not come from the database but
+// from the query being stopped, ErrCanceled when the client went away and
+// ErrUnavailable when the database could not be reached, or nil for any other error
+func interrupted(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrCanceled
+	case unavailable(err):
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// MySQL translates an error of the mysql driver, nil stays nil and any other error
+// is ErrInternal
+func MySQL(err error) error {
+	if err == nil {
+		return nil
+	}
+	if kind := interrupted(err); kind != nil {
+		return &Error{Kind: kind, Cause: err}
+	}
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return &Error{Kind: ErrInternal, Cause: err}
+	}
+	kind := ErrInternal
+	switch mysqlErr.Number {
+	case 1044, 1045:
+		kind = ErrAccessDenied
+	case 1046:
+		kind = ErrNoDatabaseSelected
+	case 1048:
+		kind = ErrNotNullColumn
+	case 1054:
+		kind = ErrUnknownColumn
+	case 1062:
+		kind = ErrDuplicateEntry
+	case 1064:
+		kind = ErrSyntaxError
+	case 1146:
+		kind = ErrTableDoesNotExist
+	case 1451, 1452:
+		kind = ErrForeignKey
+	case 1213:
+		kind = ErrDeadlock
+	case 1040:
+		kind = ErrUnavailable
+	}
+	return &Error{Kind: kind, Code: strconv.Itoa(int(mysqlErr.Number)), Cause: err}
+}
+
+// SQLite translates an error of the sqlite driver, nil stays nil and any other error
+// is ErrInternal
+func SQLite(err error) error {
+	if err == nil {
+		return nil
+	}
+	if kind := interrupted(err); kind != nil {
+		return &Error{Kind: kind, Cause: err}
+	}
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return &Error{Kind: ErrInternal, Cause: err}
+	}
+	kind := ErrInternal
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_AUTH:
+		kind = ErrAccessDenied
+	case sqlite3.SQLITE_CONSTRAINT_NOTNULL:
+		kind = ErrNotNullColumn
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		kind = ErrDuplicateEntry
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		kind = ErrForeignKey
+	default:
+		// sqlite reports the schema and syntax errors with the generic SQLITE_ERROR
+		message := sqliteErr.Error()
+		switch {
+		case strings.Contains(message, "no such table"):
+			kind = ErrTableDoesNotExist
+		case strings.Contains(message, "no such column"):
+			kind = ErrUnknownColumn
+		case strings.Contains(message, "syntax error"):
+			kind = ErrSyntaxError
+		}
+	}
+	return &Error{Kind: kind, Code: strconv.Itoa(sqliteErr.Code()), Cause: err}
+}
+
+// Postgres translates an error of the postgres driver, nil stays nil and any other
+// error is ErrInternal
+func Postgres(err error) error {
+	if err == nil {
+		return nil
+	}
+	if kind := interrupted(err); kind != nil {
+		return &Error{Kind: kind, Cause: err}
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return &Error{Kind: ErrInternal, Cause: err}
+	}
+	kind := ErrInternal
+	switch pqErr.Code.Name() {
+	case "invalid_authorization_specification", "invalid_password", "insufficient_privilege":
+		kind = ErrAccessDenied
+	case "invalid_catalog_name":
+		kind = ErrNoDatabaseSelected
+	case "not_null_violation":
+		kind = ErrNotNullColumn
+	case "undefined_column":
+		kind = ErrUnknownColumn
+	case "unique_violation":
+		kind = ErrDuplicateEntry
+	case "foreign_key_violation":
+		kind = ErrForeignKey
+	case "syntax_error":
+		kind = ErrSyntaxError
+	case "undefined_table":
+		kind = ErrTableDoesNotExist
+	case "deadlock_detected":
+		kind = ErrDeadlock
+	case "query_canceled":
+		// lib/pq cancels the statement in the server when the context is canceled
+		kind = ErrCanceled
+	case "too_many_connections", "admin_shutdown", "cannot_connect_now":
+		kind = ErrUnavailable
+	default:
+		if pqErr.Code.Class() == "08" {
+			kind = ErrUnavailable
+		}
+	}
+	return &Error{Kind: kind, Code: string(pqErr.Code), Cause: err}
+}
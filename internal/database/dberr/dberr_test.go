@@ -0,0 +1,155 @@
+package dberr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMySQL(t *testing.T) {
+	cases := []struct {
+		name   string
+		number uint16
+		exp    error
+	}{
+		{"access denied", 1045, ErrAccessDenied},
+		{"no database selected", 1046, ErrNoDatabaseSelected},
+		{"column cannot be null", 1048, ErrNotNullColumn},
+		{"unknown column", 1054, ErrUnknownColumn},
+		{"duplicate entry", 1062, ErrDuplicateEntry},
+		{"syntax error", 1064, ErrSyntaxError},
+		{"table does not exist", 1146, ErrTableDoesNotExist},
+		{"foreign key", 1452, ErrForeignKey},
+		{"referenced row", 1451, ErrForeignKey},
+		{"deadlock", 1213, ErrDeadlock},
+		{"too many connections", 1040, ErrUnavailable},
+		{"unknown number", 1205, ErrInternal},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			cause := &mysql.MySQLError{Number: c.number, Message: c.name}
+
+			// act
+			err := MySQL(fmt.Errorf("exec: %w", cause))
+
+			// assert
+			assert.ErrorIs(t, err, c.exp)
+			assert.Equal(t, c.exp.Error(), err.Error())
+			var mysqlErr *mysql.MySQLError
+			assert.True(t, errors.As(err, &mysqlErr))
+			assert.Equal(t, cause, mysqlErr)
+		})
+	}
+
+	t.Run("Success, nil", func(t *testing.T) {
+		assert.NoError(t, MySQL(nil))
+	})
+
+	t.Run("Success, unreachable database", func(t *testing.T) {
+		// arrange
+		cause := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+		// act
+		err := MySQL(cause)
+		errTimeout := MySQL(context.DeadlineExceeded)
+
+		// assert
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.ErrorIs(t, errTimeout, ErrUnavailable)
+		assert.ErrorIs(t, errTimeout, context.DeadlineExceeded)
+	})
+
+	t.Run("Success, canceled request", func(t *testing.T) {
+		// act
+		err := MySQL(fmt.Errorf("query: %w", context.Canceled))
+
+		// assert
+		assert.ErrorIs(t, err, ErrCanceled)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Success, other errors are internal and keep their cause", func(t *testing.T) {
+		// arrange
+		cause := errors.New("unexpected")
+
+		// act
+		err := MySQL(cause)
+
+		// assert
+		assert.ErrorIs(t, err, ErrInternal)
+		assert.ErrorIs(t, err, cause)
+	})
+}
+
+func TestPostgres(t *testing.T) {
+	cases := []struct {
+		name string
+		code pq.ErrorCode
+		exp  error
+	}{
+		{"duplicate entry", "23505", ErrDuplicateEntry},
+		{"foreign key", "23503", ErrForeignKey},
+		{"deadlock", "40P01", ErrDeadlock},
+		{"query canceled", "57014", ErrCanceled},
+		{"connection failure", "08006", ErrUnavailable},
+		{"table does not exist", "42P01", ErrTableDoesNotExist},
+		{"unknown code", "22012", ErrInternal},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			err := Postgres(&pq.Error{Code: c.code, Message: c.name})
+
+			// assert
+			assert.ErrorIs(t, err, c.exp)
+			var dbErr *Error
+			assert.True(t, errors.As(err, &dbErr))
+			assert.Equal(t, string(c.code), dbErr.Code)
+		})
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	// arrange
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "my_db.sqlite"))
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, database.Migrate(db, database.SQLite))
+	_, err = db.Exec(`INSERT INTO warehouses(id, name, address, telephone, capacity) VALUES (1, 'Main Warehouse', '221 Baker Street', '4555666', 100)`)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name  string
+		query string
+		exp   error
+	}{
+		{"duplicate entry", `INSERT INTO warehouses(id, name, address, telephone, capacity) VALUES (1, 'a', 'b', 'c', 1)`, ErrDuplicateEntry},
+		{"foreign key", `INSERT INTO products(name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES ('a', 1, 'X1', 1, '2030-01-01', 1, 99)`, ErrForeignKey},
+		{"column cannot be null", `INSERT INTO warehouses(name, address, telephone, capacity) VALUES (NULL, 'b', 'c', 1)`, ErrNotNullColumn},
+		{"table does not exist", `SELECT id FROM missing`, ErrTableDoesNotExist},
+		{"unknown column", `SELECT missing FROM warehouses`, ErrUnknownColumn},
+		{"syntax error", `SELEC id FROM warehouses`, ErrSyntaxError},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			_, errExec := db.Exec(c.query)
+			err := SQLite(errExec)
+
+			// assert
+			assert.ErrorIs(t, err, c.exp)
+			var dbErr *Error
+			assert.True(t, errors.As(err, &dbErr))
+			assert.Contains(t, dbErr.Detail(), errExec.Error())
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package database
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+// Errors returned by MemoryDB, the memory repositories map them like a sql driver error
+var (
+	ErrRowNotFound         = errors.New("row not found")
+	ErrUniqueViolation     = errors.New("unique constraint violation")
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+)
+
+// MemoryDB keeps products and warehouses in memory and enforces the same constraints
+// as the sql schema: auto increment ids, unique code_value and the products to
+// warehouses foreign key. It is safe for concurrent use
+type MemoryDB struct {
+	mu              sync.RWMutex
+	products        map[int]domain.Product
+	warehouses      map[int]domain.Warehouse
+	lastProductID   int
+	lastWarehouseID int
+}
+
+// NewMemoryDB creates an empty MemoryDB
+func NewMemoryDB() *MemoryDB {
+	return &MemoryDB{
+		products:   map[int]domain.Product{},
+		warehouses: map[int]domain.Warehouse{},
+	}
+}
+
+// InsertWarehouse stores the warehouse with a new id and returns it
+func (db *MemoryDB) InsertWarehouse(warehouse domain.Warehouse) domain.Warehouse {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.lastWarehouseID++
+	warehouse.Id = db.lastWarehouseID
+	db.warehouses[warehouse.Id] = warehouse
+	return warehouse
+}
+
+// Warehouse returns the warehouse with that id
+func (db *MemoryDB) Warehouse(id int) (domain.Warehouse, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	warehouse, ok := db.warehouses[id]
+	if !ok {
+		return domain.Warehouse{}, ErrRowNotFound
+	}
+	return warehouse, nil
+}
+
+// Warehouses returns every warehouse ordered by id
+func (db *MemoryDB) Warehouses() []domain.Warehouse {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	var warehouses []domain.Warehouse
+	for _, warehouse := range db.warehouses {
+		warehouses = append(warehouses, warehouse)
+	}
+	sort.Slice(warehouses, func(i, j int) bool { return warehouses[i].Id < warehouses[j].Id })
+	return warehouses
+}
+
+// UpdateWarehouse replaces the warehouse with the same id
+func (db *MemoryDB) UpdateWarehouse(warehouse domain.Warehouse) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if _, ok := db.warehouses[warehouse.Id]; !ok {
+		return ErrRowNotFound
+	}
+	db.warehouses[warehouse.Id] = warehouse
+	return nil
+}
+
+// DeleteWarehouse removes the warehouse with that id. Its products are deleted when
+// cascade is true or moved to the warehouse moveTo when it is not zero, otherwise the
+// warehouse can't be removed while they reference it. It returns how many products
+// were deleted or moved, and nothing changes when it fails
+func (db *MemoryDB) DeleteWarehouse(id int, cascade bool, moveTo int) (int, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if _, ok := db.warehouses[id]; !ok {
+		return 0, ErrRowNotFound
+	}
+	if _, ok := db.warehouses[moveTo]; moveTo != 0 && (!ok || moveTo == id) {
+		return 0, ErrForeignKeyViolation
+	}
+	var referencing []int
+	for _, product := range db.products {
+		if product.WarehouseId == id {
+			referencing = append(referencing, product.Id)
+		}
+	}
+	if len(referencing) > 0 && !cascade && moveTo == 0 {
+		return 0, ErrForeignKeyViolation
+	}
+	for _, productID := range referencing {
+		if cascade {
+			delete(db.products, productID)
+			continue
+		}
+		product := db.products[productID]
+		product.WarehouseId = moveTo
+		db.products[productID] = product
+	}
+	delete(db.warehouses, id)
+	return len(referencing), nil
+}
+
+// CountProducts returns how many products reference the warehouse
+func (db *MemoryDB) CountProducts(warehouseID int) int {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	count := 0
+	for _, product := range db.products {
+		if product.WarehouseId == warehouseID {
+			count++
+		}
+	}
+	return count
+}
+
+// checkProduct validates the unique and foreign key constraints of product
+func (db *MemoryDB) checkProduct(product domain.Product) error {
+	for _, p := range db.products {
+		if p.Id != product.Id && p.CodeValue == product.CodeValue {
+			return ErrUniqueViolation
+		}
+	}
+	if _, ok := db.warehouses[product.WarehouseId]; !ok {
+		return ErrForeignKeyViolation
+	}
+	return nil
+}
+
+// InsertProduct stores the product with a new id and returns it
+func (db *MemoryDB) InsertProduct(product domain.Product) (domain.Product, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	product.Id = 0
+	if err := db.checkProduct(product); err != nil {
+		return domain.Product{}, err
+	}
+	db.lastProductID++
+	product.Id = db.lastProductID
+	db.products[product.Id] = product
+	return product, nil
+}
+
+// UpdateProduct replaces the product with the same id
+func (db *MemoryDB) UpdateProduct(product domain.Product) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if _, ok := db.products[product.Id]; !ok {
+		return ErrRowNotFound
+	}
+	if err := db.checkProduct(product); err != nil {
+		return err
+	}
+	db.products[product.Id] = product
+	return nil
+}
+
+// DeleteProduct removes the product with that id
+func (db *MemoryDB) DeleteProduct(id int) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if _, ok := db.products[id]; !ok {
+		return ErrRowNotFound
+	}
+	delete(db.products, id)
+	return nil
+}
+
+// Product returns the product with that id
+func (db *MemoryDB) Product(id int) (domain.Product, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	product, ok := db.products[id]
+	if !ok {
+		return domain.Product{}, ErrRowNotFound
+	}
+	return product, nil
+}
+
+// Products returns every product ordered by id
+func (db *MemoryDB) Products() []domain.Product {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	var products []domain.Product
+	for _, product := range db.products {
+		products = append(products, product)
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].Id < products[j].Id })
+	return products
+}
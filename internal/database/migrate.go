@@ -0,0 +1,302 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dialect names a sql backend, the values match the -storage flag of the server
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// Errors returned by the Migrator
+var (
+	ErrUnknownDialect = errors.New("unknown sql dialect")
+	ErrNoMigration    = errors.New("no migration to revert")
+	ErrPending        = errors.New("pending migrations")
+)
+
+// migrationsFS holds one directory per dialect with files named
+// <version>_<name>.up.sql and <version>_<name>.down.sql
+//
+//go:embed migrations
+var migrationsFS embed.FS
+
+// migrationsTable tracks the applied versions, the statement is valid in every dialect
+const migrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+// Migration is a versioned schema change with the sql to apply and revert it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus tells whether a migration is applied in the database
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+// Migrations returns the embedded migrations of dialect ordered by version
+func Migrations(dialect Dialect) ([]Migration, error) {
+	dir := path.Join("migrations", string(dialect))
+	entries, err := fs.ReadDir(migrationsFS, dir)
+	if err != nil {
+		return nil, ErrUnknownDialect
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		file := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(file, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(file, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s: unexpected file name", file)
+		}
+		parts := strings.SplitN(strings.TrimSuffix(file, "."+direction+".sql"), "_", 2)
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) != 2 {
+			return nil, fmt.Errorf("migration %s: unexpected file name", file)
+		}
+		content, err := migrationsFS.ReadFile(path.Join(dir, file))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d %s: up and down files are required", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator applies and reverts the embedded migrations of a dialect, the applied
+// versions are stored in the schema_migrations table
+type Migrator struct {
+	database   *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// NewMigrator creates a Migrator for the database, which must be of dialect
+func NewMigrator(database *sql.DB, dialect Dialect) (*Migrator, error) {
+	migrations, err := Migrations(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{database, dialect, migrations}, nil
+}
+
+// Migrate applies every pending migration of dialect to the database
+func Migrate(database *sql.DB, dialect Dialect) error {
+	migrator, err := NewMigrator(database, dialect)
+	if err != nil {
+		return err
+	}
+	return migrator.Up()
+}
+
+// Placeholder returns the bind parameter n, starting at 1, of the dialect
+func (d Dialect) Placeholder(n int) string {
+	if d == Postgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// applied returns the applied versions, creating the migrations table when it is missing
+func (m *Migrator) applied() (map[int]bool, error) {
+	if _, err := m.database.Exec(migrationsTable); err != nil {
+		return nil, err
+	}
+	rows, err := m.database.Query(`SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions[version] = true
+	}
+	return versions, rows.Err()
+}
+
+// run executes the statements of script and records the change in schema_migrations
+// inside one transaction. MySQL commits DDL implicitly, so there a failed migration
+// can leave its first statements applied
+func (m *Migrator) run(script, record string, args ...interface{}) error {
+	tx, err := m.database.Begin()
+	if err != nil {
+		return err
+	}
+	for _, statement := range splitStatements(script) {
+		if _, err := tx.Exec(statement); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Up applies every pending migration in version order
+func (m *Migrator) Up() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	record := fmt.Sprintf(`INSERT INTO schema_migrations(version, name) VALUES (%s, %s)`, m.dialect.Placeholder(1), m.dialect.Placeholder(2))
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			continue
+		}
+		if err := m.run(migration.Up, record, migration.Version, migration.Name); err != nil {
+			return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// Down reverts the last applied migration, it returns ErrNoMigration when there is none
+func (m *Migrator) Down() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	record := fmt.Sprintf(`DELETE FROM schema_migrations WHERE version = %s`, m.dialect.Placeholder(1))
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if !applied[migration.Version] {
+			continue
+		}
+		if err := m.run(migration.Down, record, migration.Version); err != nil {
+			return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+		return nil
+	}
+	return ErrNoMigration
+}
+
+// Reset runs every down migration, applied or not, and applies them all again. The
+// down files drop the tables only if they exist, so Reset also wipes a database whose
+// tables were created before the migrations were tracked
+func (m *Migrator) Reset() error {
+	if _, err := m.applied(); err != nil {
+		return err
+	}
+	record := fmt.Sprintf(`DELETE FROM schema_migrations WHERE version = %s`, m.dialect.Placeholder(1))
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if err := m.run(migration.Down, record, migration.Version); err != nil {
+			return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return m.Up()
+}
+
+// Version returns the highest applied version, zero when nothing is applied
+func (m *Migrator) Version() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+// Check returns ErrPending when the database is not at the version of the last
+// migration. Unlike Version it only reads schema_migrations, so it can run on every
+// readiness probe
+func (m *Migrator) Check(ctx context.Context) error {
+	var version int
+	err := m.database.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return err
+	}
+	latest := 0
+	if len(m.migrations) > 0 {
+		latest = m.migrations[len(m.migrations)-1].Version
+	}
+	if version != latest {
+		return fmt.Errorf("%w: schema version %d, expected %d", ErrPending, version, latest)
+	}
+	return nil
+}
+
+// Status returns every known migration and whether it is applied
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	status := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status = append(status, MigrationStatus{migration, applied[migration.Version]})
+	}
+	return status, nil
+}
+
+// splitStatements splits a sql script on the semicolons that end a line, so the
+// files can hold several statements even where the driver runs only one per Exec
+func splitStatements(script string) []string {
+	var statements []string
+	for _, statement := range strings.Split(script, ";\n") {
+		statement = strings.TrimSuffix(strings.TrimSpace(statement), ";")
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
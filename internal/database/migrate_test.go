@@ -0,0 +1,175 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newMigrator crea un Migrator sobre una base sqlite temporal vacia
+func newMigrator(t *testing.T) *Migrator {
+	t.Helper()
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "my_db.sqlite"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	migrator, err := NewMigrator(db, SQLite)
+	assert.NoError(t, err)
+	return migrator
+}
+
+func TestMigrations(t *testing.T) {
+	t.Run("Success, every dialect has the same versions", func(t *testing.T) {
+		// arrange
+		exp := []string{"create_warehouses", "create_products", "products_name_search", "products_code_value_lower"}
+
+		for _, dialect := range []Dialect{MySQL, SQLite, Postgres} {
+			// act
+			migrations, err := Migrations(dialect)
+
+			// assert
+			assert.NoError(t, err)
+			var names []string
+			for i, migration := range migrations {
+				assert.Equal(t, i+1, migration.Version)
+				assert.NotEmpty(t, migration.Up)
+				assert.NotEmpty(t, migration.Down)
+				names = append(names, migration.Name)
+			}
+			assert.Equal(t, exp, names, dialect)
+		}
+	})
+
+	t.Run("failed, unknown dialect", func(t *testing.T) {
+		// act
+		migrations, err := Migrations("oracle")
+
+		// assert
+		assert.ErrorIs(t, err, ErrUnknownDialect)
+		assert.Empty(t, migrations)
+	})
+}
+
+func TestMigrator_Up(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+
+		// act
+		err := migrator.Up()
+		version, errVersion := migrator.Version()
+		_, errInsert := migrator.database.Exec(`INSERT INTO warehouses(name, address, telephone, capacity) VALUES ('Main Warehouse', '221 Baker Street', '4555666', 100)`)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, errVersion)
+		assert.NoError(t, errInsert)
+		assert.Equal(t, 4, version)
+	})
+
+	t.Run("Success, nothing pending", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+		assert.NoError(t, migrator.Up())
+
+		// act
+		err := migrator.Up()
+		status, errStatus := migrator.Status()
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, errStatus)
+		assert.Len(t, status, 4)
+		for _, migration := range status {
+			assert.True(t, migration.Applied)
+		}
+	})
+}
+
+func TestMigrator_Down(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+		assert.NoError(t, migrator.Up())
+
+		// act
+		err := migrator.Down()
+		version, errVersion := migrator.Version()
+		status, errStatus := migrator.Status()
+		var indexes int
+		errIndex := migrator.database.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'products_code_value_lower'`).Scan(&indexes)
+		_, errProducts := migrator.database.Exec(`SELECT code_value FROM products`)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, errVersion)
+		assert.NoError(t, errStatus)
+		assert.NoError(t, errIndex)
+		assert.Equal(t, 3, version)
+		assert.True(t, status[2].Applied)
+		assert.False(t, status[3].Applied)
+		assert.Zero(t, indexes)
+		assert.NoError(t, errProducts)
+	})
+
+	t.Run("failed, nothing applied", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+
+		// act
+		err := migrator.Down()
+
+		// assert
+		assert.ErrorIs(t, err, ErrNoMigration)
+	})
+}
+
+func TestMigrator_Check(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+		assert.NoError(t, migrator.Up())
+
+		// act
+		err := migrator.Check(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed, pending migration", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+		assert.NoError(t, migrator.Up())
+		assert.NoError(t, migrator.Down())
+
+		// act
+		err := migrator.Check(context.Background())
+
+		// assert
+		assert.ErrorIs(t, err, ErrPending)
+	})
+
+	t.Run("failed, never migrated", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+
+		// act
+		err := migrator.Check(context.Background())
+
+		// assert
+		assert.Error(t, err)
+	})
+}
+
+func TestSplitStatements(t *testing.T) {
+	// arrange
+	script := "CREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);\n"
+
+	// act
+	statements := splitStatements(script)
+
+	// assert
+	assert.Equal(t, []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"}, statements)
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS warehouses (
+    id INT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    address VARCHAR(255) NOT NULL,
+    telephone VARCHAR(255) NOT NULL,
+    capacity INT NOT NULL,
+    PRIMARY KEY (id)
+) ENGINE = InnoDB;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS products (
+    id INT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL,
+    code_value VARCHAR(255) NOT NULL,
+    is_published TINYINT(1) NOT NULL DEFAULT 0,
+    expiration DATE NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    id_warehouse INT NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY products_code_value_unique (code_value),
+    CONSTRAINT products_id_warehouse_fk FOREIGN KEY (id_warehouse) REFERENCES warehouses (id)
+) ENGINE = InnoDB;
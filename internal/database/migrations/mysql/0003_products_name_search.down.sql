@@ -0,0 +1,5 @@
+-- mysql has no DROP INDEX IF EXISTS, Reset runs this file on databases without the index
+SET @drop_index = IF((SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = 'products' AND index_name = 'products_name_fulltext') > 0, 'ALTER TABLE products DROP INDEX products_name_fulltext', 'DO 0');
+PREPARE drop_index FROM @drop_index;
+EXECUTE drop_index;
+DEALLOCATE PREPARE drop_index;
@@ -0,0 +1 @@
+ALTER TABLE products ADD FULLTEXT INDEX products_name_fulltext (name);
@@ -0,0 +1 @@
+ALTER TABLE products MODIFY code_value VARCHAR(255) NOT NULL;
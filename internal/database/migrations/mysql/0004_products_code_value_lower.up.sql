@@ -0,0 +1,2 @@
+-- the code lookups ignore case, so code_value does not depend on the default collation
+ALTER TABLE products MODIFY code_value VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS products (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL,
+    code_value VARCHAR(255) NOT NULL UNIQUE,
+    is_published BOOLEAN NOT NULL DEFAULT FALSE,
+    expiration DATE NOT NULL,
+    price NUMERIC(10, 2) NOT NULL,
+    id_warehouse INTEGER NOT NULL REFERENCES warehouses (id)
+);
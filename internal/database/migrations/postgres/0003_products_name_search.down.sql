@@ -0,0 +1 @@
+DROP INDEX IF EXISTS products_name_search;
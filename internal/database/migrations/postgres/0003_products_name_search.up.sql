@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS products_name_search ON products USING GIN (to_tsvector('simple', name));
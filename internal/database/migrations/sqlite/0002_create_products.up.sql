@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS products (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    code_value TEXT NOT NULL UNIQUE,
+    is_published BOOLEAN NOT NULL DEFAULT 0,
+    expiration DATE NOT NULL,
+    price REAL NOT NULL,
+    id_warehouse INTEGER NOT NULL REFERENCES warehouses (id)
+);
@@ -0,0 +1,7 @@
+-- products_fts indexes the names of products, the triggers keep it in sync. The
+-- migrator splits statements on ";" at the end of a line, so each trigger is one line
+CREATE VIRTUAL TABLE IF NOT EXISTS products_fts USING fts5(name, content = 'products', content_rowid = 'id');
+CREATE TRIGGER IF NOT EXISTS products_fts_insert AFTER INSERT ON products BEGIN INSERT INTO products_fts(rowid, name) VALUES (new.id, new.name); END;
+CREATE TRIGGER IF NOT EXISTS products_fts_delete AFTER DELETE ON products BEGIN INSERT INTO products_fts(products_fts, rowid, name) VALUES ('delete', old.id, old.name); END;
+CREATE TRIGGER IF NOT EXISTS products_fts_update AFTER UPDATE OF name ON products BEGIN INSERT INTO products_fts(products_fts, rowid, name) VALUES ('delete', old.id, old.name); INSERT INTO products_fts(rowid, name) VALUES (new.id, new.name); END;
+INSERT INTO products_fts(products_fts) VALUES ('rebuild');
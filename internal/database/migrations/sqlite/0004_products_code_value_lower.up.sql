@@ -0,0 +1,2 @@
+-- products_code_value_lower lets the code lookups ignore case like the mysql collation
+CREATE INDEX IF NOT EXISTS products_code_value_lower ON products (LOWER(code_value));
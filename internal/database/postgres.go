@@ -0,0 +1,13 @@
+package database
+
+import (
+	"database/sql"
+
+	_ "github.com/lib/pq"
+)
+
+// OpenPostgres opens the postgres database at dsn without connecting, use Wait to
+// check the connection. The tables are created by the postgres migrations
+func OpenPostgres(dsn string) (*sql.DB, error) {
+	return sql.Open("postgres", dsn)
+}
@@ -0,0 +1,287 @@
+// Package query builds parameterized SELECT statements for the repositories. Column
+// names only come from the whitelist of a Table and every value goes as a bind
+// argument, so user input never ends up in the sql text
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+)
+
+// Errors returned when building a statement
+var (
+	ErrUnknownColumn   = errors.New("unknown column")
+	ErrUnknownOperator = errors.New("unknown operator")
+)
+
+// Op is a comparison operator of a condition
+type Op string
+
+const (
+	Eq Op = "="
+	Ne Op = "<>"
+	Lt Op = "<"
+	Le Op = "<="
+	Gt Op = ">"
+	Ge Op = ">="
+)
+
+func (o Op) valid() bool {
+	switch o {
+	case Eq, Ne, Lt, Le, Gt, Ge:
+		return true
+	}
+	return false
+}
+
+// Table is a table with the columns that can be selected, filtered and sorted
+type Table struct {
+	Name    string
+	Columns []string
+}
+
+// Has tells whether column is in the whitelist of the table
+func (t Table) Has(column string) bool {
+	for _, c := range t.Columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+type condition struct {
+	column string
+	op     Op
+	value  interface{}
+	// in holds the values of an IN condition, op and value are not used
+	in []interface{}
+	// like holds the patterns of a LIKE condition, op and value are not used
+	like []string
+	// lower compares the column in lowercase in an IN condition
+	lower bool
+}
+
+type order struct {
+	column string
+	desc   bool
+}
+
+// Builder composes a SELECT over one table. The methods can be chained and the
+// first invalid column or operator is returned by Select and Count
+type Builder struct {
+	dialect database.Dialect
+	table   Table
+	where   []condition
+	orderBy []order
+	limit   int
+	offset  int
+	err     error
+}
+
+// New starts a statement over table written for dialect
+func New(dialect database.Dialect, table Table) *Builder {
+	return &Builder{dialect: dialect, table: table}
+}
+
+// Where adds the condition column op value, conditions are joined with AND
+func (b *Builder) Where(column string, op Op, value interface{}) *Builder {
+	if b.check(column) && !op.valid() {
+		b.err = fmt.Errorf("%w %q", ErrUnknownOperator, op)
+	}
+	b.where = append(b.where, condition{column: column, op: op, value: value})
+	return b
+}
+
+// WhereIn adds the condition column IN (values), with no values no row matches
+func (b *Builder) WhereIn(column string, values ...interface{}) *Builder {
+	b.check(column)
+	if values == nil {
+		values = []interface{}{}
+	}
+	b.where = append(b.where, condition{column: column, in: values})
+	return b
+}
+
+// WhereInLower adds the condition column in lowercase IN (values), so the values
+// have to be lowercase. With no values no row matches
+func (b *Builder) WhereInLower(column string, values ...string) *Builder {
+	b.check(column)
+	in := make([]interface{}, len(values))
+	for i, value := range values {
+		in[i] = value
+	}
+	b.where = append(b.where, condition{column: column, in: in, lower: true})
+	return b
+}
+
+// WhereLike adds the condition that column in lowercase matches any of the LIKE
+// patterns, so the patterns have to be lowercase. With no patterns no row matches
+func (b *Builder) WhereLike(column string, patterns ...string) *Builder {
+	b.check(column)
+	if patterns == nil {
+		patterns = []string{}
+	}
+	b.where = append(b.where, condition{column: column, like: patterns})
+	return b
+}
+
+// OrderBy adds column to the sort order, after the columns added before
+func (b *Builder) OrderBy(column string, desc bool) *Builder {
+	b.check(column)
+	b.orderBy = append(b.orderBy, order{column, desc})
+	return b
+}
+
+// Limit sets the maximum number of rows, zero means no limit
+func (b *Builder) Limit(n int) *Builder {
+	b.limit = n
+	return b
+}
+
+// Offset sets the number of rows to skip
+func (b *Builder) Offset(n int) *Builder {
+	b.offset = n
+	return b
+}
+
+// Select returns the statement selecting columns, or every column of the table when
+// none is given, and its arguments
+func (b *Builder) Select(columns ...string) (string, []interface{}, error) {
+	if len(columns) == 0 {
+		columns = b.table.Columns
+	}
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		b.check(column)
+		quoted[i] = b.quote(column)
+	}
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	var sql strings.Builder
+	fmt.Fprintf(&sql, "SELECT %s FROM %s", strings.Join(quoted, ", "), b.quote(b.table.Name))
+	args := b.writeWhere(&sql)
+	if len(b.orderBy) > 0 {
+		terms := make([]string, len(b.orderBy))
+		for i, o := range b.orderBy {
+			terms[i] = b.quote(o.column)
+			if o.desc {
+				terms[i] += " DESC"
+			}
+		}
+		sql.WriteString(" ORDER BY " + strings.Join(terms, ", "))
+	}
+	if b.limit > 0 {
+		args = append(args, b.limit)
+		sql.WriteString(" LIMIT " + b.dialect.Placeholder(len(args)))
+	}
+	if b.offset > 0 {
+		// mysql has no OFFSET without LIMIT, so there the limit is the largest row count
+		if b.limit == 0 && b.dialect == database.MySQL {
+			sql.WriteString(" LIMIT 18446744073709551615")
+		}
+		args = append(args, b.offset)
+		sql.WriteString(" OFFSET " + b.dialect.Placeholder(len(args)))
+	}
+	return sql.String(), args, nil
+}
+
+// Count returns the statement counting the rows that match the conditions, the
+// order and the limits are ignored
+func (b *Builder) Count() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	var sql strings.Builder
+	sql.WriteString("SELECT COUNT(*) FROM " + b.quote(b.table.Name))
+	args := b.writeWhere(&sql)
+	return sql.String(), args, nil
+}
+
+// writeWhere writes the WHERE clause and returns its arguments
+func (b *Builder) writeWhere(sql *strings.Builder) []interface{} {
+	var args []interface{}
+	for i, c := range b.where {
+		if i == 0 {
+			sql.WriteString(" WHERE ")
+		} else {
+			sql.WriteString(" AND ")
+		}
+		if c.in != nil {
+			args = b.writeIn(sql, c, args)
+			continue
+		}
+		if c.like != nil {
+			args = b.writeLike(sql, c, args)
+			continue
+		}
+		args = append(args, c.value)
+		fmt.Fprintf(sql, "%s %s %s", b.quote(c.column), c.op, b.dialect.Placeholder(len(args)))
+	}
+	return args
+}
+
+// writeIn writes an IN condition and returns args with its values. An empty list
+// is not valid sql, so it is written as a condition that is always false
+func (b *Builder) writeIn(sql *strings.Builder, c condition, args []interface{}) []interface{} {
+	if len(c.in) == 0 {
+		sql.WriteString("1 = 0")
+		return args
+	}
+	placeholders := make([]string, len(c.in))
+	for i, value := range c.in {
+		args = append(args, value)
+		placeholders[i] = b.dialect.Placeholder(len(args))
+	}
+	column := b.quote(c.column)
+	if c.lower {
+		column = "LOWER(" + column + ")"
+	}
+	fmt.Fprintf(sql, "%s IN (%s)", column, strings.Join(placeholders, ", "))
+	return args
+}
+
+// writeLike writes a LIKE condition and returns args with its patterns, like
+// writeIn an empty list is a condition that is always false
+func (b *Builder) writeLike(sql *strings.Builder, c condition, args []interface{}) []interface{} {
+	if len(c.like) == 0 {
+		sql.WriteString("1 = 0")
+		return args
+	}
+	terms := make([]string, len(c.like))
+	for i, pattern := range c.like {
+		args = append(args, pattern)
+		terms[i] = fmt.Sprintf("LOWER(%s) LIKE %s", b.quote(c.column), b.dialect.Placeholder(len(args)))
+	}
+	if len(terms) == 1 {
+		sql.WriteString(terms[0])
+	} else {
+		sql.WriteString("(" + strings.Join(terms, " OR ") + ")")
+	}
+	return args
+}
+
+// check records an error when column is not in the whitelist
+func (b *Builder) check(column string) bool {
+	if b.table.Has(column) {
+		return true
+	}
+	if b.err == nil {
+		b.err = fmt.Errorf("%w %q in %s", ErrUnknownColumn, column, b.table.Name)
+	}
+	return false
+}
+
+// quote quotes an identifier, the whitelist already keeps the names safe and the
+// quotes let columns use reserved words
+func (b *Builder) quote(name string) string {
+	if b.dialect == database.MySQL {
+		return "`" + name + "`"
+	}
+	return `"` + name + `"`
+}
@@ -0,0 +1,208 @@
+package query
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+var warehouses = Table{Name: "warehouses", Columns: []string{"id", "name", "capacity"}}
+
+func TestBuilder_Select(t *testing.T) {
+	cases := []struct {
+		name    string
+		dialect database.Dialect
+		exp     string
+	}{
+		{"mysql", database.MySQL, "SELECT `id`, `name` FROM `warehouses` WHERE `capacity` >= ? AND `name` <> ? ORDER BY `capacity` DESC, `id` LIMIT ? OFFSET ?"},
+		{"sqlite", database.SQLite, `SELECT "id", "name" FROM "warehouses" WHERE "capacity" >= ? AND "name" <> ? ORDER BY "capacity" DESC, "id" LIMIT ? OFFSET ?`},
+		{"postgres", database.Postgres, `SELECT "id", "name" FROM "warehouses" WHERE "capacity" >= $1 AND "name" <> $2 ORDER BY "capacity" DESC, "id" LIMIT $3 OFFSET $4`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			sql, args, err := New(c.dialect, warehouses).
+				Where("capacity", Ge, 100).
+				Where("name", Ne, "SuperMarket").
+				OrderBy("capacity", true).
+				OrderBy("id", false).
+				Limit(10).
+				Offset(20).
+				Select("id", "name")
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, sql)
+			assert.Equal(t, []interface{}{100, "SuperMarket", 10, 20}, args)
+		})
+	}
+
+	t.Run("Success, every column and no clauses", func(t *testing.T) {
+		// act
+		sql, args, err := New(database.SQLite, warehouses).Select()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT "id", "name", "capacity" FROM "warehouses"`, sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("Success, mysql offset without limit", func(t *testing.T) {
+		// act
+		sql, args, err := New(database.MySQL, warehouses).Offset(5).Select("id")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT `id` FROM `warehouses` LIMIT 18446744073709551615 OFFSET ?", sql)
+		assert.Equal(t, []interface{}{5}, args)
+	})
+
+	t.Run("failed, column out of the whitelist", func(t *testing.T) {
+		// act
+		sql, args, err := New(database.MySQL, warehouses).OrderBy("name; DROP TABLE warehouses", false).Select()
+
+		// assert
+		assert.ErrorIs(t, err, ErrUnknownColumn)
+		assert.Empty(t, sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("failed, unknown operator", func(t *testing.T) {
+		// act
+		_, _, err := New(database.MySQL, warehouses).Where("id", Op("= 1 OR 1 ="), 1).Select()
+
+		// assert
+		assert.ErrorIs(t, err, ErrUnknownOperator)
+	})
+}
+
+func TestBuilder_WhereIn(t *testing.T) {
+	t.Run("Success, a placeholder per value", func(t *testing.T) {
+		// act
+		sql, args, err := New(database.Postgres, warehouses).Where("capacity", Gt, 0).WhereIn("name", "Main", "Big").Select("id")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT "id" FROM "warehouses" WHERE "capacity" > $1 AND "name" IN ($2, $3)`, sql)
+		assert.Equal(t, []interface{}{0, "Main", "Big"}, args)
+	})
+
+	t.Run("Success, no values match no row", func(t *testing.T) {
+		// act
+		sql, args, err := New(database.MySQL, warehouses).WhereIn("name").Count()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(*) FROM `warehouses` WHERE 1 = 0", sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("failed, column out of the whitelist", func(t *testing.T) {
+		// act
+		_, _, err := New(database.SQLite, warehouses).WhereIn("password", "x").Select()
+
+		// assert
+		assert.ErrorIs(t, err, ErrUnknownColumn)
+	})
+}
+
+func TestBuilder_WhereInLower(t *testing.T) {
+	t.Run("Success, column in lowercase", func(t *testing.T) {
+		// act
+		sql, args, err := New(database.SQLite, warehouses).WhereInLower("name", "main", "big").Select("id")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT "id" FROM "warehouses" WHERE LOWER("name") IN (?, ?)`, sql)
+		assert.Equal(t, []interface{}{"main", "big"}, args)
+	})
+
+	t.Run("Success, no values match no row", func(t *testing.T) {
+		// act
+		sql, _, err := New(database.Postgres, warehouses).WhereInLower("name").Count()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT COUNT(*) FROM "warehouses" WHERE 1 = 0`, sql)
+	})
+}
+
+func TestBuilder_WhereLike(t *testing.T) {
+	t.Run("Success, patterns joined with OR", func(t *testing.T) {
+		// act
+		sql, args, err := New(database.MySQL, warehouses).WhereLike("name", "%main%", "%big%").WhereLike("name", "%st%").Select("id")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT `id` FROM `warehouses` WHERE (LOWER(`name`) LIKE ? OR LOWER(`name`) LIKE ?) AND LOWER(`name`) LIKE ?", sql)
+		assert.Equal(t, []interface{}{"%main%", "%big%", "%st%"}, args)
+	})
+
+	t.Run("Success, no patterns match no row", func(t *testing.T) {
+		// act
+		sql, args, err := New(database.Postgres, warehouses).WhereLike("name").Count()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT COUNT(*) FROM "warehouses" WHERE 1 = 0`, sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("failed, column out of the whitelist", func(t *testing.T) {
+		// act
+		_, _, err := New(database.SQLite, warehouses).WhereLike("password", "%x%").Select()
+
+		// assert
+		assert.ErrorIs(t, err, ErrUnknownColumn)
+	})
+}
+
+func TestBuilder_Count(t *testing.T) {
+	t.Run("Success, ignores order and limits", func(t *testing.T) {
+		// act
+		sql, args, err := New(database.Postgres, warehouses).Where("capacity", Lt, 50).OrderBy("name", false).Limit(5).Count()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT COUNT(*) FROM "warehouses" WHERE "capacity" < $1`, sql)
+		assert.Equal(t, []interface{}{50}, args)
+	})
+
+	t.Run("failed, column out of the whitelist", func(t *testing.T) {
+		// act
+		_, _, err := New(database.Postgres, warehouses).Where("password", Eq, "x").Count()
+
+		// assert
+		assert.ErrorIs(t, err, ErrUnknownColumn)
+	})
+}
+
+// TestBuilder_SQLite runs a built statement, the arguments must bind in order
+func TestBuilder_SQLite(t *testing.T) {
+	// arrange
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "query.sqlite"))
+	assert.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE warehouses (id INTEGER PRIMARY KEY, name TEXT, capacity INTEGER);
+	INSERT INTO warehouses VALUES (1, 'Main', 100), (2, 'Small', 10), (3, 'Big', 500), (4, 'Other', 300)`)
+	assert.NoError(t, err)
+	sql, args, err := New(database.SQLite, warehouses).Where("capacity", Ge, 100).OrderBy("capacity", true).Limit(2).Offset(1).Select("name")
+	assert.NoError(t, err)
+
+	// act
+	rows, err := db.Query(sql, args...)
+	assert.NoError(t, err)
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		assert.NoError(t, rows.Scan(&name))
+		names = append(names, name)
+	}
+
+	// assert
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, []string{"Other", "Main"}, names)
+}
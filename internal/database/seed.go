@@ -0,0 +1,82 @@
+package database
+
+import (
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+// ErrNotEmpty is returned by Seed when the tables already have rows
+var ErrNotEmpty = errors.New("database is not empty")
+
+// The fixtures are the rows the repository tests expect, with expirations in yyyy-mm-dd
+var (
+	//go:embed fixtures/warehouses.json
+	warehousesFixture []byte
+	//go:embed fixtures/products.json
+	productsFixture []byte
+)
+
+// Fixtures returns the seed warehouses and products
+func Fixtures() ([]domain.Warehouse, []domain.Product, error) {
+	var warehouses []domain.Warehouse
+	if err := json.Unmarshal(warehousesFixture, &warehouses); err != nil {
+		return nil, nil, err
+	}
+	var products []domain.Product
+	if err := json.Unmarshal(productsFixture, &products); err != nil {
+		return nil, nil, err
+	}
+	return warehouses, products, nil
+}
+
+// Seed loads the fixtures into an empty, migrated database of dialect keeping their
+// ids, so the rows match the ones the txdb tests assume
+func Seed(database *sql.DB, dialect Dialect) error {
+	warehouses, products, err := Fixtures()
+	if err != nil {
+		return err
+	}
+	var count int
+	if err := database.QueryRow(`SELECT (SELECT COUNT(*) FROM warehouses) + (SELECT COUNT(*) FROM products)`).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrNotEmpty
+	}
+
+	tx, err := database.Begin()
+	if err != nil {
+		return err
+	}
+	insertWarehouse := fmt.Sprintf(`INSERT INTO warehouses(id, name, address, telephone, capacity) VALUES (%s, %s, %s, %s, %s)`,
+		dialect.Placeholder(1), dialect.Placeholder(2), dialect.Placeholder(3), dialect.Placeholder(4), dialect.Placeholder(5))
+	for _, warehouse := range warehouses {
+		if _, err := tx.Exec(insertWarehouse, warehouse.Id, warehouse.Name, warehouse.Address, warehouse.Telephone, warehouse.Capacity); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("seed warehouse %d: %w", warehouse.Id, err)
+		}
+	}
+	insertProduct := fmt.Sprintf(`INSERT INTO products(id, name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES (%s, %s, %s, %s, %s, %s, %s, %s)`,
+		dialect.Placeholder(1), dialect.Placeholder(2), dialect.Placeholder(3), dialect.Placeholder(4), dialect.Placeholder(5), dialect.Placeholder(6), dialect.Placeholder(7), dialect.Placeholder(8))
+	for _, product := range products {
+		if _, err := tx.Exec(insertProduct, product.Id, product.Name, product.Quantity, product.CodeValue, product.IsPublished, product.Expiration, product.Price, product.WarehouseId); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("seed product %d: %w", product.Id, err)
+		}
+	}
+	// postgres sequences do not move with explicit ids
+	if dialect == Postgres {
+		for _, table := range []string{"warehouses", "products"} {
+			if _, err := tx.Exec(fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('%s', 'id'), (SELECT MAX(id) FROM %s))`, table, table)); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeed(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+		assert.NoError(t, migrator.Up())
+
+		// act
+		err := Seed(migrator.database, SQLite)
+
+		// assert
+		assert.NoError(t, err)
+		var name string
+		assert.NoError(t, migrator.database.QueryRow(`SELECT w.name FROM products p INNER JOIN warehouses w ON p.id_warehouse = w.id WHERE p.code_value = 'M4637'`).Scan(&name))
+		assert.Equal(t, "SuperMarket", name)
+	})
+
+	t.Run("failed, database not empty", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+		assert.NoError(t, migrator.Up())
+		assert.NoError(t, Seed(migrator.database, SQLite))
+
+		// act
+		err := Seed(migrator.database, SQLite)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotEmpty)
+	})
+}
+
+func TestMigrator_Reset(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+		assert.NoError(t, migrator.Up())
+		assert.NoError(t, Seed(migrator.database, SQLite))
+
+		// act
+		err := migrator.Reset()
+		version, errVersion := migrator.Version()
+		errSeed := Seed(migrator.database, SQLite)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, errVersion)
+		assert.Equal(t, 4, version)
+		assert.NoError(t, errSeed)
+	})
+}
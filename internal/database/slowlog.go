@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Querier is the part of *sql.DB and *sql.Tx that runs queries
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Conn is the part of *sql.DB the sql repositories use, so they can run on a plain
+// *sql.DB or on a SlowQueryLogger
+type Conn interface {
+	Querier
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// SlowQueryLogger runs the queries on a *sql.DB and logs the ones that take at least
+// Threshold, with the sql text, the types of the arguments, the duration and the
+// repository method that ran them. QueryContext is measured until the rows are
+// returned, not until they are read. The queries of the transactions started with
+// InTx are logged the same way
+type SlowQueryLogger struct {
+	database *sql.DB
+	// querier runs the queries, the database or the transaction of observeTx
+	querier   Querier
+	threshold time.Duration
+	logger    *log.Logger
+}
+
+// NewSlowQueryLogger creates a SlowQueryLogger over database that writes to logger,
+// or to the standard logger when logger is nil
+func NewSlowQueryLogger(database *sql.DB, threshold time.Duration, logger *log.Logger) *SlowQueryLogger {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &SlowQueryLogger{database: database, querier: database, threshold: threshold, logger: logger}
+}
+
+// ExecContext runs ExecContext on the database and logs it when it is slow
+func (l *SlowQueryLogger) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	result, err := l.querier.ExecContext(ctx, query, args...)
+	l.observe(start, query, args, err)
+	return result, err
+}
+
+// QueryContext runs QueryContext on the database and logs it when it is slow
+func (l *SlowQueryLogger) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := l.querier.QueryContext(ctx, query, args...)
+	l.observe(start, query, args, err)
+	return rows, err
+}
+
+// QueryRowContext runs QueryRowContext on the database and logs it when it is slow
+func (l *SlowQueryLogger) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := l.querier.QueryRowContext(ctx, query, args...)
+	l.observe(start, query, args, row.Err())
+	return row
+}
+
+// BeginTx starts a transaction on the database. The queries run directly on the
+// *sql.Tx are not logged, InTx runs them through observeTx
+func (l *SlowQueryLogger) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return l.database.BeginTx(ctx, opts)
+}
+
+// observeTx returns a Querier that runs the queries on tx and logs the slow ones
+func (l *SlowQueryLogger) observeTx(tx *sql.Tx) Querier {
+	return &SlowQueryLogger{database: l.database, querier: tx, threshold: l.threshold, logger: l.logger}
+}
+
+// observe logs the query when it ran for at least the threshold
+func (l *SlowQueryLogger) observe(start time.Time, query string, args []interface{}, err error) {
+	duration := time.Since(start)
+	if duration < l.threshold {
+		return
+	}
+	message := fmt.Sprintf("slow query: duration=%s caller=%s sql=%q args=%s", duration, caller(), strings.Join(strings.Fields(query), " "), argsShape(args))
+	if err != nil {
+		message += fmt.Sprintf(" error=%q", err)
+	}
+	l.logger.Println(message)
+}
+
+// argsShape describes the arguments by type, the values are left out of the log
+func argsShape(args []interface{}) string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = fmt.Sprintf("%T", arg)
+	}
+	return "[" + strings.Join(types, " ") + "]"
+}
+
+// caller returns the first function outside this file that is on the stack, which is
+// the repository method that ran the query
+func caller() string {
+	pcs := make([]uintptr, 8)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasSuffix(frame.File, "/slowlog.go") {
+			function := frame.Function[strings.LastIndex(frame.Function, "/")+1:]
+			return fmt.Sprintf("%s (%s:%d)", function, frame.File[strings.LastIndex(frame.File, "/")+1:], frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowQueryLogger(t *testing.T) {
+	t.Run("Success, logs slow queries", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+		assert.NoError(t, migrator.Up())
+		var out bytes.Buffer
+		conn := NewSlowQueryLogger(migrator.database, 0, log.New(&out, "", 0))
+
+		// act
+		_, err := conn.ExecContext(context.Background(), `INSERT INTO warehouses(name, address, telephone, capacity)
+			VALUES (?, ?, ?, ?)`, "Main Warehouse", "221 Baker Street", "4555666", 100)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), `sql="INSERT INTO warehouses(name, address, telephone, capacity) VALUES (?, ?, ?, ?)"`)
+		assert.Contains(t, out.String(), "args=[string string string int]")
+		assert.Contains(t, out.String(), "caller=database.TestSlowQueryLogger.func1 (slowlog_test.go:")
+		assert.NotContains(t, out.String(), "Main Warehouse")
+	})
+
+	t.Run("Success, logs queries of a transaction", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+		assert.NoError(t, migrator.Up())
+		var out bytes.Buffer
+		conn := NewSlowQueryLogger(migrator.database, 0, log.New(&out, "", 0))
+
+		// act
+		err := InTx(context.Background(), conn, func(tx Querier) error {
+			_, err := tx.ExecContext(context.Background(), `DELETE FROM products WHERE id_warehouse = ?`, 1)
+			return err
+		})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), `sql="DELETE FROM products WHERE id_warehouse = ?" args=[int]`)
+	})
+
+	t.Run("Success, fast queries are not logged", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+		assert.NoError(t, migrator.Up())
+		var out bytes.Buffer
+		conn := NewSlowQueryLogger(migrator.database, time.Hour, log.New(&out, "", 0))
+
+		// act
+		rows, err := conn.QueryContext(context.Background(), `SELECT id FROM warehouses`)
+		assert.NoError(t, err)
+		rows.Close()
+		var count int
+		errRow := conn.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM warehouses`).Scan(&count)
+
+		// assert
+		assert.NoError(t, errRow)
+		assert.Empty(t, out.String())
+	})
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Timeouts holds the query deadline of each operation, like "warehouses.report".
+// Operations without an entry use Default, and zero means no deadline
+type Timeouts struct {
+	Default    time.Duration
+	Operations map[string]time.Duration
+}
+
+// For returns the deadline of operation
+func (t *Timeouts) For(operation string) time.Duration {
+	if timeout, ok := t.Operations[operation]; ok {
+		return timeout
+	}
+	return t.Default
+}
+
+// String writes the operation deadlines as operation=duration pairs separated by commas
+func (t *Timeouts) String() string {
+	if t == nil {
+		return ""
+	}
+	var pairs []string
+	for operation, timeout := range t.Operations {
+		pairs = append(pairs, operation+"="+timeout.String())
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set parses operation=duration pairs separated by commas and adds them to the
+// operation deadlines, so Timeouts can be used as a flag.Value
+func (t *Timeouts) Set(value string) error {
+	if t.Operations == nil {
+		t.Operations = map[string]time.Duration{}
+	}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return fmt.Errorf("invalid timeout %q, use operation=duration", pair)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil || timeout < 0 {
+			return fmt.Errorf("invalid timeout %q, use operation=duration", pair)
+		}
+		t.Operations[strings.TrimSpace(parts[0])] = timeout
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeouts(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		timeouts := Timeouts{Default: time.Second}
+
+		// act
+		err := timeouts.Set("warehouses.report=30s, products.get=500ms")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 30*time.Second, timeouts.For("warehouses.report"))
+		assert.Equal(t, 500*time.Millisecond, timeouts.For("products.get"))
+		assert.Equal(t, time.Second, timeouts.For("products.list"))
+		assert.Equal(t, "products.get=500ms,warehouses.report=30s", timeouts.String())
+	})
+
+	t.Run("failed, invalid pair", func(t *testing.T) {
+		for _, value := range []string{"warehouses.report", "=1s", "products.get=soon", "products.get=-1s"} {
+			// arrange
+			var timeouts Timeouts
+
+			// act
+			err := timeouts.Set(value)
+
+			// assert
+			assert.Error(t, err, value)
+		}
+	})
+}
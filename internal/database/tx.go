@@ -0,0 +1,31 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// txObserver is a Conn that also watches the queries of its transactions, like
+// SlowQueryLogger
+type txObserver interface {
+	observeTx(tx *sql.Tx) Querier
+}
+
+// InTx runs fn in a transaction of conn. The transaction is committed when fn returns
+// nil and rolled back when it returns an error, which InTx returns unchanged. When
+// conn is a SlowQueryLogger the queries fn runs on tx are logged like any other
+func InTx(ctx context.Context, conn Conn, fn func(tx Querier) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	var querier Querier = tx
+	if observer, ok := conn.(txObserver); ok {
+		querier = observer.observeTx(tx)
+	}
+	if err := fn(querier); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Delays between the pings of Wait, they double after each failed attempt
+var (
+	waitInitialDelay = 250 * time.Millisecond
+	waitMaxDelay     = 5 * time.Second
+)
+
+// Wait pings the database until it answers, so the server can start before the
+// database is up. Between attempts it waits with an exponential backoff and logs the
+// error to logger, or to the standard logger when logger is nil. It gives up when
+// ctx is done and returns the last ping error
+func Wait(ctx context.Context, database *sql.DB, logger *log.Logger) error {
+	if logger == nil {
+		logger = log.Default()
+	}
+	delay := waitInitialDelay
+	for attempt := 1; ; attempt++ {
+		err := database.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		logger.Printf("database not ready (attempt %d): %v, retrying in %s", attempt, err, delay)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database not ready after %d attempts: %w", attempt, err)
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > waitMaxDelay {
+			delay = waitMaxDelay
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWait(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		migrator := newMigrator(t)
+		var out bytes.Buffer
+
+		// act
+		err := Wait(context.Background(), migrator.database, log.New(&out, "", 0))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("failed, database never answers", func(t *testing.T) {
+		// arrange
+		db, err := sql.Open("postgres", "postgres://postgres@127.0.0.1:1/my_db?sslmode=disable&connect_timeout=1")
+		assert.NoError(t, err)
+		defer db.Close()
+		waitInitialDelay, waitMaxDelay = 10*time.Millisecond, 20*time.Millisecond
+		defer func() { waitInitialDelay, waitMaxDelay = 250*time.Millisecond, 5*time.Second }()
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		var out bytes.Buffer
+
+		// act
+		err = Wait(ctx, db, log.New(&out, "", 0))
+
+		// assert
+		assert.Error(t, err)
+		assert.Greater(t, strings.Count(out.String(), "database not ready"), 1)
+	})
+}
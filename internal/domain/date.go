@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DateLayout es el formato ISO 8601 con el que se escriben las fechas en json y en
+// la base
+const DateLayout = "2006-01-02"
+
+// LegacyDateLayout es el formato dd/mm/yyyy que la api aceptaba antes de Date
+const LegacyDateLayout = "02/01/2006"
+
+// ErrInvalidDate indica que un texto no es una fecha en ninguno de los formatos
+var ErrInvalidDate = errors.New("invalid date")
+
+// Date es una fecha sin hora, siempre en UTC. En json se escribe como yyyy-mm-dd y
+// la fecha cero como null
+type Date struct {
+	time.Time
+}
+
+// NewDate crea la fecha year-month-day
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// dateOf devuelve el dia de t, sin la hora ni la zona
+func dateOf(t time.Time) Date {
+	return NewDate(t.Date())
+}
+
+// DateFormats son los formatos en que se leen las fechas de los requests, en orden
+type DateFormats []string
+
+// NewDateFormats devuelve los formatos de las fechas de los requests, yyyy-mm-dd y
+// tambien dd/mm/yyyy si legacy es true
+func NewDateFormats(legacy bool) DateFormats {
+	if legacy {
+		return DateFormats{DateLayout, LegacyDateLayout}
+	}
+	return DateFormats{DateLayout}
+}
+
+// Parse lee value con el primer formato que coincida, las fechas que no existen,
+// como 31/02, son un error
+func (f DateFormats) Parse(value string) (Date, error) {
+	for _, layout := range f {
+		if t, err := time.Parse(layout, value); err == nil {
+			return dateOf(t), nil
+		}
+	}
+	return Date{}, fmt.Errorf("%w %q, use yyyy-mm-dd", ErrInvalidDate, value)
+}
+
+// String devuelve la fecha como yyyy-mm-dd, vacio para la fecha cero
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateLayout)
+}
+
+// MarshalJSON escribe la fecha como yyyy-mm-dd
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON lee la fecha en los dos formatos, los archivos escritos antes de Date
+// tienen dd/mm/yyyy. null y el texto vacio son la fecha cero. Los requests no pasan
+// por aca, traen expiration como texto y la leen al validarse con sus DateFormats
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Date{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidDate, data)
+	}
+	if value == "" {
+		*d = Date{}
+		return nil
+	}
+	date, err := NewDateFormats(true).Parse(value)
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
+
+// Scan lee una columna DATE, los drivers la devuelven como time.Time o como texto
+func (d *Date) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = dateOf(value)
+		return nil
+	case []byte:
+		return d.scanText(string(value))
+	case string:
+		return d.scanText(value)
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidDate, src)
+	}
+}
+
+// scanText lee una fecha escrita por la base, con o sin hora
+func (d *Date) scanText(value string) error {
+	for _, layout := range []string{DateLayout, time.RFC3339, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			*d = dateOf(t)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %q", ErrInvalidDate, value)
+}
+
+// Value escribe la fecha como yyyy-mm-dd, que entienden todas las bases, y la fecha
+// cero como NULL
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateFormats_Parse(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+		exp   Date
+		err   bool
+	}{
+		{"Success, iso", "2024-02-29", NewDate(2024, 2, 29), false},
+		{"Success, legacy", "15/12/2021", NewDate(2021, 12, 15), false},
+		{"failed, day and month out of range", "99/99/2024", Date{}, true},
+		{"failed, day out of the month", "31/02/2024", Date{}, true},
+		{"failed, not a leap year", "2023-02-29", Date{}, true},
+		{"failed, text", "tomorrow", Date{}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			date, err := NewDateFormats(true).Parse(c.value)
+
+			// assert
+			if c.err {
+				assert.ErrorIs(t, err, ErrInvalidDate)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, c.exp, date)
+		})
+	}
+
+	t.Run("failed, legacy format disabled", func(t *testing.T) {
+		// act
+		_, err := NewDateFormats(false).Parse("15/12/2021")
+
+		// assert
+		assert.ErrorIs(t, err, ErrInvalidDate)
+	})
+}
+
+func TestDate_JSON(t *testing.T) {
+	t.Run("Success, round trip keeps the iso format", func(t *testing.T) {
+		// arrange
+		var product Product
+
+		// act
+		errIn := json.Unmarshal([]byte(`{"expiration":"15/12/2021"}`), &product)
+		out, errOut := json.Marshal(product)
+
+		// assert
+		assert.NoError(t, errIn)
+		assert.NoError(t, errOut)
+		assert.Equal(t, NewDate(2021, 12, 15), product.Expiration)
+		assert.Contains(t, string(out), `"expiration":"2021-12-15"`)
+	})
+
+	t.Run("Success, null and empty are the zero date", func(t *testing.T) {
+		for _, input := range []string{`{"expiration":null}`, `{"expiration":""}`, `{}`} {
+			// act
+			var product Product
+			err := json.Unmarshal([]byte(input), &product)
+
+			// assert
+			assert.NoError(t, err)
+			assert.True(t, product.Expiration.IsZero(), input)
+		}
+	})
+
+	t.Run("failed, invalid date", func(t *testing.T) {
+		// act
+		var product Product
+		err := json.Unmarshal([]byte(`{"expiration":"31/02/2024"}`), &product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrInvalidDate)
+	})
+}
+
+func TestDate_Scan(t *testing.T) {
+	cases := []struct {
+		name string
+		src  interface{}
+		exp  Date
+	}{
+		{"time", time.Date(2021, 12, 15, 0, 0, 0, 0, time.FixedZone("ART", -3*60*60)), NewDate(2021, 12, 15)},
+		{"text", "2021-12-15", NewDate(2021, 12, 15)},
+		{"bytes", []byte("2021-12-15"), NewDate(2021, 12, 15)},
+		{"rfc3339", "2021-12-15T00:00:00Z", NewDate(2021, 12, 15)},
+		{"null", nil, Date{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			var date Date
+			err := date.Scan(c.src)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, date)
+		})
+	}
+
+	t.Run("Success, value", func(t *testing.T) {
+		// act
+		value, err := NewDate(2021, 12, 15).Value()
+		zero, errZero := Date{}.Value()
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, errZero)
+		assert.Equal(t, driver.Value("2021-12-15"), value)
+		assert.Nil(t, zero)
+	})
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "strings"
+
+// FieldError es un problema con un campo, Field es el nombre del campo en el json
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError indica que un dato enviado por el cliente no es valido, Fields
+// tiene el problema de cada campo
+type ValidationError struct {
+	Message string
+	Fields  []FieldError
+}
+
+// Error devuelve el mensaje seguido por los problemas de cada campo
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+	problems := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		problems[i] = field.Field + " " + field.Message
+	}
+	return e.Message + ": " + strings.Join(problems, ", ")
+}
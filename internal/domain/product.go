@@ -2,13 +2,39 @@ package domain
 
 type Product struct {
 	Id          int     `json:"id"`
-	Name        string  `json:"name" binding:"required"`
-	Quantity    int     `json:"quantity" binding:"required"`
-	CodeValue   string  `json:"code_value" binding:"required"`
+	Name        string  `json:"name"`
+	Quantity    int     `json:"quantity"`
+	CodeValue   string  `json:"code_value"`
 	IsPublished bool    `json:"is_published"`
-	Expiration  string  `json:"expiration" binding:"required"`
-	Price       float64 `json:"price" binding:"required"`
-	WarehouseId int     `json:"id_warehouse" binding:"required"`
+	Expiration  Date    `json:"expiration"`
+	Price       float64 `json:"price"`
+	WarehouseId int     `json:"id_warehouse"`
+}
+
+// ProductRequest es un producto como llega en el body de un request. Expiration es
+// texto y se lee al validar, asi una fecha invalida se informa junto con los demas
+// campos
+type ProductRequest struct {
+	Name        string  `json:"name"`
+	Quantity    int     `json:"quantity"`
+	CodeValue   string  `json:"code_value"`
+	IsPublished bool    `json:"is_published"`
+	Expiration  string  `json:"expiration"`
+	Price       float64 `json:"price"`
+	WarehouseId int     `json:"id_warehouse"`
+}
+
+// product devuelve el producto del request con la fecha expiration ya leida
+func (r ProductRequest) product(expiration Date) Product {
+	return Product{
+		Name:        r.Name,
+		Quantity:    r.Quantity,
+		CodeValue:   r.CodeValue,
+		IsPublished: r.IsPublished,
+		Expiration:  expiration,
+		Price:       r.Price,
+		WarehouseId: r.WarehouseId,
+	}
 }
 
 type ProductFull struct {
@@ -0,0 +1,96 @@
+package domain
+
+import "strings"
+
+// Validator junta los problemas de los campos de un dato, para informarlos todos
+// juntos en lugar de cortar en el primero
+type Validator struct {
+	fields []FieldError
+}
+
+// Check agrega el problema message del campo field cuando ok es false
+func (v *Validator) Check(ok bool, field, message string) {
+	if !ok {
+		v.fields = append(v.fields, FieldError{Field: field, Message: message})
+	}
+}
+
+// Required revisa que value no este vacio
+func (v *Validator) Required(value, field string) {
+	v.Check(strings.TrimSpace(value) != "", field, "can't be empty")
+}
+
+// Err devuelve un ValidationError con todos los problemas, o nil si no hay ninguno
+func (v *Validator) Err(message string) error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Message: message, Fields: v.fields}
+}
+
+// Date lee value con dates, si no es una fecha agrega el problema del campo field
+func (v *Validator) Date(value, field string, dates DateFormats) Date {
+	date, err := dates.Parse(value)
+	if err != nil {
+		v.Check(false, field, err.Error())
+	}
+	return date
+}
+
+// Validate revisa todos los campos del producto y lo devuelve con expiration leida
+// con dates
+func (r ProductRequest) Validate(dates DateFormats) (Product, error) {
+	var v Validator
+	v.Required(r.Name, "name")
+	v.Required(r.CodeValue, "code_value")
+	v.Check(r.Quantity > 0, "quantity", "must be greater than 0")
+	v.Check(r.Price > 0, "price", "must be greater than 0")
+	var expiration Date
+	if v.Required(r.Expiration, "expiration"); strings.TrimSpace(r.Expiration) != "" {
+		expiration = v.Date(r.Expiration, "expiration", dates)
+	}
+	v.Check(r.WarehouseId > 0, "id_warehouse", "must be greater than 0")
+	if err := v.Err("invalid product"); err != nil {
+		return Product{}, err
+	}
+	return r.product(expiration), nil
+}
+
+// ValidatePatch revisa los campos de una actualizacion parcial del producto, los
+// campos con su valor cero no se actualizan y no se revisan
+func (r ProductRequest) ValidatePatch(dates DateFormats) (Product, error) {
+	var v Validator
+	v.Check(r.Name == "" || strings.TrimSpace(r.Name) != "", "name", "can't be empty")
+	v.Check(r.CodeValue == "" || strings.TrimSpace(r.CodeValue) != "", "code_value", "can't be empty")
+	v.Check(r.Quantity >= 0, "quantity", "must be greater than 0")
+	v.Check(r.Price >= 0, "price", "must be greater than 0")
+	var expiration Date
+	if r.Expiration != "" {
+		expiration = v.Date(r.Expiration, "expiration", dates)
+	}
+	if err := v.Err("invalid product"); err != nil {
+		return Product{}, err
+	}
+	return r.product(expiration), nil
+}
+
+// Validate revisa todos los campos del warehouse
+func (w Warehouse) Validate() error {
+	var v Validator
+	v.Required(w.Name, "name")
+	v.Required(w.Address, "address")
+	v.Required(w.Telephone, "telephone")
+	v.Check(w.Capacity > 0, "capacity", "must be greater than 0")
+	return v.Err("invalid warehouse")
+}
+
+// ValidatePatch revisa los campos de una actualizacion parcial del warehouse, los
+// campos con su valor cero no se actualizan y no se revisan
+func (w Warehouse) ValidatePatch() error {
+	var v Validator
+	v.Check(w.Name == "" || strings.TrimSpace(w.Name) != "", "name", "can't be empty")
+	v.Check(w.Address == "" || strings.TrimSpace(w.Address) != "", "address", "can't be empty")
+	v.Check(w.Telephone == "" || strings.TrimSpace(w.Telephone) != "", "telephone", "can't be empty")
+	v.Check(w.Capacity >= 0, "capacity", "must be greater than 0")
+	return v.Err("invalid warehouse")
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductRequest_Validate(t *testing.T) {
+	valid := ProductRequest{Name: "Oil - Margarine", Quantity: 439, CodeValue: "S82254D", IsPublished: true, Expiration: "15/12/2021", Price: 71.42, WarehouseId: 1}
+
+	cases := []struct {
+		name   string
+		update func(p *ProductRequest)
+		exp    []FieldError
+	}{
+		{"Success", func(p *ProductRequest) {}, nil},
+		{"failed, every field", func(p *ProductRequest) { *p = ProductRequest{} }, []FieldError{
+			{"name", "can't be empty"},
+			{"code_value", "can't be empty"},
+			{"quantity", "must be greater than 0"},
+			{"price", "must be greater than 0"},
+			{"expiration", "can't be empty"},
+			{"id_warehouse", "must be greater than 0"},
+		}},
+		{"failed, blank name", func(p *ProductRequest) { p.Name = "   " }, []FieldError{{"name", "can't be empty"}}},
+		{"failed, day and month out of range", func(p *ProductRequest) { p.Expiration = "99/99/2024" }, []FieldError{{"expiration", `invalid date "99/99/2024", use yyyy-mm-dd`}}},
+		{"failed, day out of the month", func(p *ProductRequest) { p.Expiration = "31/02/2024" }, []FieldError{{"expiration", `invalid date "31/02/2024", use yyyy-mm-dd`}}},
+		{"failed, date and other fields", func(p *ProductRequest) { p.Name = ""; p.Expiration = "99/99/2024" }, []FieldError{
+			{"name", "can't be empty"},
+			{"expiration", `invalid date "99/99/2024", use yyyy-mm-dd`},
+		}},
+		{"Success, iso date", func(p *ProductRequest) { p.Expiration = "2024-02-01" }, nil},
+		{"Success, leap day", func(p *ProductRequest) { p.Expiration = "29/02/2024" }, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			request := valid
+			c.update(&request)
+
+			// act
+			product, err := request.Validate(NewDateFormats(true))
+
+			// assert
+			if c.exp == nil {
+				assert.NoError(t, err)
+				assert.False(t, product.Expiration.IsZero())
+				return
+			}
+			var validation *ValidationError
+			assert.True(t, errors.As(err, &validation))
+			assert.Equal(t, c.exp, validation.Fields)
+		})
+	}
+
+	t.Run("Success, reads the date", func(t *testing.T) {
+		// arrange
+		exp := Product{Name: "Oil - Margarine", Quantity: 439, CodeValue: "S82254D", IsPublished: true, Expiration: NewDate(2021, 12, 15), Price: 71.42, WarehouseId: 1}
+
+		// act
+		product, err := valid.Validate(NewDateFormats(true))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, product)
+	})
+
+	t.Run("failed, legacy date disabled", func(t *testing.T) {
+		// act
+		_, err := valid.Validate(NewDateFormats(false))
+
+		// assert
+		assert.EqualError(t, err, `invalid product: expiration invalid date "15/12/2021", use yyyy-mm-dd`)
+	})
+}
+
+func TestProductRequest_ValidatePatch(t *testing.T) {
+	t.Run("Success, empty patch", func(t *testing.T) {
+		// act
+		product, err := ProductRequest{}.ValidatePatch(NewDateFormats(true))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, Product{}, product)
+	})
+
+	t.Run("failed, invalid fields", func(t *testing.T) {
+		// arrange
+		exp := `invalid product: name can't be empty, quantity must be greater than 0, expiration invalid date "30/02/2030", use yyyy-mm-dd`
+
+		// act
+		_, err := ProductRequest{Name: " ", Quantity: -1, Expiration: "30/02/2030"}.ValidatePatch(NewDateFormats(true))
+
+		// assert
+		assert.EqualError(t, err, exp)
+	})
+}
+
+func TestWarehouse_Validate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// act
+		err := Warehouse{Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100}.Validate()
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed, every field", func(t *testing.T) {
+		// arrange
+		exp := []FieldError{
+			{"name", "can't be empty"},
+			{"address", "can't be empty"},
+			{"telephone", "can't be empty"},
+			{"capacity", "must be greater than 0"},
+		}
+
+		// act
+		err := Warehouse{}.Validate()
+
+		// assert
+		var validation *ValidationError
+		assert.True(t, errors.As(err, &validation))
+		assert.Equal(t, exp, validation.Fields)
+	})
+}
+
+func TestWarehouse_ValidatePatch(t *testing.T) {
+	t.Run("Success, empty patch", func(t *testing.T) {
+		// act
+		err := Warehouse{}.ValidatePatch()
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed, invalid fields", func(t *testing.T) {
+		// arrange
+		exp := "invalid warehouse: address can't be empty, capacity must be greater than 0"
+
+		// act
+		err := Warehouse{Address: " ", Capacity: -1}.ValidatePatch()
+
+		// assert
+		assert.EqualError(t, err, exp)
+	})
+}
@@ -2,9 +2,9 @@ package domain
 
 type Warehouse struct {
 	Id        int    `json:"id"`
-	Name      string `json:"name" binding:"required"`
-	Address   string `json:"address" binding:"required"`
-	Telephone string `json:"telephone" binding:"required"`
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	Telephone string `json:"telephone"`
 	Capacity  int    `json:"capacity"`
 }
 
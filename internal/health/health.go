@@ -0,0 +1,122 @@
+// Package health checks the dependencies of the server for the readiness probe
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+)
+
+// Status of a dependency or of the whole report
+type Status string
+
+const (
+	Up   Status = "up"
+	Down Status = "down"
+)
+
+// CheckFunc checks one dependency, it returns nil when the dependency is healthy
+type CheckFunc func(ctx context.Context) error
+
+// Check is the result of a CheckFunc
+type Check struct {
+	Status    Status  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the result of every check, Status is Down when any check failed
+type Report struct {
+	Status Status           `json:"status"`
+	Checks map[string]Check `json:"checks"`
+}
+
+// Checker runs the checks of the dependencies the server needs to serve requests
+type Checker struct {
+	timeout time.Duration
+	checks  map[string]CheckFunc
+}
+
+// NewChecker creates a Checker with no checks. Each check gets at most timeout to
+// answer, zero means no deadline
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout, checks: map[string]CheckFunc{}}
+}
+
+// Add registers check under name, a check with the same name is replaced
+func (c *Checker) Add(name string, check CheckFunc) {
+	c.checks[name] = check
+}
+
+// Run runs every check concurrently and reports their status and latency
+func (c *Checker) Run(ctx context.Context) Report {
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]Check, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, check CheckFunc) {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}(i, c.checks[name])
+	}
+	wg.Wait()
+
+	report := Report{Status: Up, Checks: make(map[string]Check, len(names))}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status == Down {
+			report.Status = Down
+		}
+	}
+	return report
+}
+
+// run runs one check with the deadline of the Checker
+func (c *Checker) run(ctx context.Context, check CheckFunc) Check {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := check(ctx)
+	result := Check{Status: Up, LatencyMS: float64(time.Since(start)) / float64(time.Millisecond)}
+	if err != nil {
+		result.Status = Down
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Ping checks that the database answers
+func Ping(db *sql.DB) CheckFunc {
+	return db.PingContext
+}
+
+// Migrations checks that every migration is applied to the database
+func Migrations(migrator *database.Migrator) CheckFunc {
+	return migrator.Check
+}
+
+// Pool checks that the connection pool has a free connection or room to open one,
+// when every connection is in use the requests wait in the pool until they time out
+func Pool(db *sql.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		stats := db.Stats()
+		if stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections {
+			return fmt.Errorf("connection pool exhausted: %d of %d connections in use", stats.InUse, stats.MaxOpenConnections)
+		}
+		return nil
+	}
+}
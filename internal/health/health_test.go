@@ -0,0 +1,124 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_Run(t *testing.T) {
+	t.Run("Success, every check up", func(t *testing.T) {
+		// arrange
+		checker := NewChecker(time.Second)
+		checker.Add("a", func(ctx context.Context) error { return nil })
+		checker.Add("b", func(ctx context.Context) error { return nil })
+
+		// act
+		report := checker.Run(context.Background())
+
+		// assert
+		assert.Equal(t, Up, report.Status)
+		assert.Len(t, report.Checks, 2)
+		assert.Equal(t, Up, report.Checks["a"].Status)
+		assert.Empty(t, report.Checks["a"].Error)
+	})
+
+	t.Run("Success, no checks", func(t *testing.T) {
+		// act
+		report := NewChecker(time.Second).Run(context.Background())
+
+		// assert
+		assert.Equal(t, Up, report.Status)
+		assert.Empty(t, report.Checks)
+	})
+
+	t.Run("failed, one check down", func(t *testing.T) {
+		// arrange
+		checker := NewChecker(time.Second)
+		checker.Add("a", func(ctx context.Context) error { return nil })
+		checker.Add("b", func(ctx context.Context) error { return errors.New("connection refused") })
+
+		// act
+		report := checker.Run(context.Background())
+
+		// assert
+		assert.Equal(t, Down, report.Status)
+		assert.Equal(t, Up, report.Checks["a"].Status)
+		assert.Equal(t, Down, report.Checks["b"].Status)
+		assert.Equal(t, "connection refused", report.Checks["b"].Error)
+	})
+
+	t.Run("failed, check times out", func(t *testing.T) {
+		// arrange
+		checker := NewChecker(10 * time.Millisecond)
+		checker.Add("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		// act
+		report := checker.Run(context.Background())
+
+		// assert
+		assert.Equal(t, Down, report.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+		assert.GreaterOrEqual(t, report.Checks["slow"].LatencyMS, 10.0)
+	})
+}
+
+func TestDatabaseChecks(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "my_db.sqlite"))
+		assert.NoError(t, err)
+		defer db.Close()
+		migrator, err := database.NewMigrator(db, database.SQLite)
+		assert.NoError(t, err)
+		assert.NoError(t, migrator.Up())
+		checker := NewChecker(time.Second)
+		checker.Add("database", Ping(db))
+		checker.Add("migrations", Migrations(migrator))
+		checker.Add("pool", Pool(db))
+
+		// act
+		report := checker.Run(context.Background())
+
+		// assert
+		assert.Equal(t, Up, report.Status, report)
+	})
+
+	t.Run("failed, pool exhausted", func(t *testing.T) {
+		// arrange
+		db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "my_db.sqlite"))
+		assert.NoError(t, err)
+		defer db.Close()
+		db.SetMaxOpenConns(1)
+		conn, err := db.Conn(context.Background())
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		// act
+		err = Pool(db)(context.Background())
+
+		// assert
+		assert.EqualError(t, err, "connection pool exhausted: 1 of 1 connections in use")
+	})
+
+	t.Run("failed, database closed", func(t *testing.T) {
+		// arrange
+		db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "my_db.sqlite"))
+		assert.NoError(t, err)
+		db.Close()
+
+		// act
+		err = Ping(db)(context.Background())
+
+		// assert
+		assert.Error(t, err)
+	})
+}
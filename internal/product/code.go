@@ -0,0 +1,118 @@
+package product
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/query"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+// MaxCodes es la mayor cantidad de codigos de una busqueda por code_value
+const MaxCodes = 100
+
+// CodeLookup es el resultado de buscar productos por code_value. Products sigue el
+// orden de los codigos pedidos y Missing tiene los codigos que no existen
+type CodeLookup struct {
+	Products []domain.Product `json:"products"`
+	Missing  []string         `json:"missing"`
+}
+
+// NewCodes limpia los codigos pedidos, les saca los espacios de los extremos y los
+// repetidos sin distinguir mayusculas, como la busqueda, y valida que haya entre 1 y
+// MaxCodes
+func NewCodes(codes []string) ([]string, error) {
+	var v domain.Validator
+	clean := make([]string, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for i, code := range codes {
+		code = strings.TrimSpace(code)
+		v.Check(code != "", fmt.Sprintf("codes[%d]", i), "can't be blank")
+		if code == "" || seen[strings.ToLower(code)] {
+			continue
+		}
+		seen[strings.ToLower(code)] = true
+		clean = append(clean, code)
+	}
+	v.Check(len(codes) > 0, "codes", "must have at least one code")
+	v.Check(len(clean) <= MaxCodes, "codes", fmt.Sprintf("can't have more than %d codes", MaxCodes))
+	if err := v.Err("invalid codes"); err != nil {
+		return nil, err
+	}
+	return clean, nil
+}
+
+// newCodeLookup ordena products como codes y junta los codigos sin producto. Los
+// codigos no distinguen mayusculas en ninguna base, como la collation de mysql que
+// devuelve S82254D cuando se pide s82254d. Si hay dos productos que solo difieren en
+// mayusculas se prefiere el exacto
+func newCodeLookup(codes []string, products []domain.Product) CodeLookup {
+	byCode := make(map[string]domain.Product, len(products))
+	byFolded := make(map[string]domain.Product, len(products))
+	for _, p := range products {
+		byCode[p.CodeValue] = p
+		byFolded[strings.ToLower(p.CodeValue)] = p
+	}
+	lookup := CodeLookup{Products: []domain.Product{}, Missing: []string{}}
+	for _, code := range codes {
+		p, ok := byCode[code]
+		if !ok {
+			p, ok = byFolded[strings.ToLower(code)]
+		}
+		if ok {
+			lookup.Products = append(lookup.Products, p)
+		} else {
+			lookup.Missing = append(lookup.Missing, code)
+		}
+	}
+	return lookup
+}
+
+// productByCode elige entre los products que devolvio la busqueda de code el que le
+// corresponde, con la misma regla que newCodeLookup
+func productByCode(code string, products []domain.Product) (domain.Product, error) {
+	lookup := newCodeLookup([]string{code}, products)
+	if len(lookup.Products) == 0 {
+		return domain.Product{}, ErrNotFound
+	}
+	return lookup.Products[0], nil
+}
+
+// productsByCode filtra en memoria los productos con alguno de los codes sin
+// distinguir mayusculas, para los repositorios que no tienen indices
+func productsByCode(products []domain.Product, codes []string) []domain.Product {
+	wanted := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		wanted[strings.ToLower(code)] = true
+	}
+	var found []domain.Product
+	for _, p := range products {
+		if wanted[strings.ToLower(p.CodeValue)] {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// codesSQL arma la consulta de los productos con alguno de los codes sin distinguir
+// mayusculas. En mysql la collation ya no las distingue y la resuelve el indice unico
+// de code_value, en sqlite y postgres el indice de LOWER(code_value)
+func codesSQL(codes []string, dialect database.Dialect) (statement, error) {
+	b := query.New(dialect, productTable)
+	if dialect == database.MySQL {
+		values := make([]interface{}, len(codes))
+		for i, code := range codes {
+			values[i] = code
+		}
+		b.WhereIn("code_value", values...)
+	} else {
+		lower := make([]string, len(codes))
+		for i, code := range codes {
+			lower[i] = strings.ToLower(code)
+		}
+		b.WhereInLower("code_value", lower...)
+	}
+	sql, args, err := b.OrderBy("id", false).Select()
+	return statement{sql, args}, err
+}
@@ -0,0 +1,120 @@
+package product
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCodes(t *testing.T) {
+	t.Run("Success, trimmed and without repeated", func(t *testing.T) {
+		// act
+		codes, err := NewCodes([]string{" A-1", "B-2", "A-1 ", "C-3"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"A-1", "B-2", "C-3"}, codes)
+	})
+
+	t.Run("Success, repeated without case", func(t *testing.T) {
+		// act
+		codes, err := NewCodes([]string{"s82254d", "S82254D", "M4637"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"s82254d", "M4637"}, codes)
+	})
+
+	t.Run("failed, no codes", func(t *testing.T) {
+		// act
+		_, err := NewCodes(nil)
+
+		// assert
+		assert.EqualError(t, err, "invalid codes: codes must have at least one code")
+	})
+
+	t.Run("failed, blank and too many codes", func(t *testing.T) {
+		// arrange
+		codes := []string{"", "  "}
+		for i := 0; i <= MaxCodes; i++ {
+			codes = append(codes, strconv.Itoa(i))
+		}
+
+		// act
+		_, err := NewCodes(codes)
+
+		// assert
+		var validation *domain.ValidationError
+		assert.True(t, errors.As(err, &validation))
+		assert.Equal(t, []domain.FieldError{
+			{Field: "codes[0]", Message: "can't be blank"},
+			{Field: "codes[1]", Message: "can't be blank"},
+			{Field: "codes", Message: "can't have more than 100 codes"},
+		}, validation.Fields)
+	})
+}
+
+func TestNewCodeLookup(t *testing.T) {
+	t.Run("Success, in the order of the codes", func(t *testing.T) {
+		// arrange
+		products := []domain.Product{{Id: 1, CodeValue: "A-1"}, {Id: 3, CodeValue: "C-3"}}
+
+		// act
+		lookup := newCodeLookup([]string{"C-3", "B-2", "A-1"}, products)
+
+		// assert
+		assert.Equal(t, CodeLookup{Products: []domain.Product{products[1], products[0]}, Missing: []string{"B-2"}}, lookup)
+	})
+
+	t.Run("Success, mixed case codes", func(t *testing.T) {
+		// arrange
+		// mysql devuelve S82254D cuando se pide s82254d
+		products := []domain.Product{{Id: 1, CodeValue: "S82254D"}, {Id: 2, CodeValue: "ab-1"}, {Id: 3, CodeValue: "AB-1"}}
+
+		// act
+		lookup := newCodeLookup([]string{"s82254d", "AB-1", "ab-1", "X-9"}, products)
+
+		// assert
+		assert.Equal(t, CodeLookup{Products: []domain.Product{products[0], products[2], products[1]}, Missing: []string{"X-9"}}, lookup)
+	})
+}
+
+func TestCodesSQL(t *testing.T) {
+	t.Run("Success, lowercase in postgres", func(t *testing.T) {
+		// act
+		stmt, err := codesSQL([]string{"A-1", "b-2"}, database.Postgres)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT "id", "name", "quantity", "code_value", "is_published", "expiration", "price", "id_warehouse" FROM "products" WHERE LOWER("code_value") IN ($1, $2) ORDER BY "id"`, stmt.sql)
+		assert.Equal(t, []interface{}{"a-1", "b-2"}, stmt.args)
+	})
+
+	t.Run("Success, the collation ignores case in mysql", func(t *testing.T) {
+		// act
+		stmt, err := codesSQL([]string{"A-1", "b-2"}, database.MySQL)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT `id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse` FROM `products` WHERE `code_value` IN (?, ?) ORDER BY `id`", stmt.sql)
+		assert.Equal(t, []interface{}{"A-1", "b-2"}, stmt.args)
+	})
+}
+
+func TestProductsByCode(t *testing.T) {
+	// arrange
+	products := []domain.Product{{Id: 1, CodeValue: "S82254D"}, {Id: 2, CodeValue: "M4637"}}
+
+	// act
+	found := productsByCode(products, []string{"s82254d"})
+	exact, err := productByCode("s82254d", found)
+
+	// assert
+	assert.Equal(t, products[:1], found)
+	assert.NoError(t, err)
+	assert.Equal(t, products[0], exact)
+}
@@ -0,0 +1,114 @@
+package product_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-txdb"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/product"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/product/producttest"
+	"github.com/bootcamp-go/consignas-go-db.git/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	if dsn := os.Getenv("MYSQL_DSN"); dsn != "" {
+		txdb.Register("txdb_mysql", "mysql", dsn)
+	}
+}
+
+// mainWarehouse es el warehouse que tienen todas las bases de los tests
+var mainWarehouse = domain.Warehouse{Id: 1, Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100}
+
+func TestConformanceJSON(t *testing.T) {
+	setup := func(t *testing.T) (product.Repository, domain.Warehouse) {
+		dir := t.TempDir()
+		productsPath := filepath.Join(dir, "products.json")
+		warehousesPath := filepath.Join(dir, "warehouses.json")
+		assert.NoError(t, os.WriteFile(productsPath, []byte(`[]`), 0644))
+		assert.NoError(t, os.WriteFile(warehousesPath, []byte(`[{"id":1,"name":"Main Warehouse","address":"221 Baker Street","telephone":"4555666","capacity":100}]`), 0644))
+		return product.NewRepository(store.NewJsonStore(productsPath, warehousesPath)), mainWarehouse
+	}
+	producttest.RunRepositoryTests(t, setup)
+	producttest.RunSearchTests(t, setup)
+}
+
+func TestConformanceJSONJournal(t *testing.T) {
+	setup := func(t *testing.T) (product.Repository, domain.Warehouse) {
+		dir := t.TempDir()
+		productsPath := filepath.Join(dir, "products.json")
+		warehousesPath := filepath.Join(dir, "warehouses.json")
+		assert.NoError(t, os.WriteFile(productsPath, []byte(`[]`), 0644))
+		assert.NoError(t, os.WriteFile(warehousesPath, []byte(`[{"id":1,"name":"Main Warehouse","address":"221 Baker Street","telephone":"4555666","capacity":100}]`), 0644))
+		storage := store.NewLogStore(productsPath, warehousesPath, store.DefaultCompactEvery)
+		t.Cleanup(func() { storage.Close() })
+		return product.NewRepository(storage), mainWarehouse
+	}
+	producttest.RunRepositoryTests(t, setup)
+	producttest.RunSearchTests(t, setup)
+}
+
+func TestConformanceMemory(t *testing.T) {
+	setup := func(t *testing.T) (product.Repository, domain.Warehouse) {
+		db := database.NewMemoryDB()
+		return product.NewMemoryRepository(db), db.InsertWarehouse(mainWarehouse)
+	}
+	producttest.RunRepositoryTests(t, setup)
+	producttest.RunSearchTests(t, setup)
+}
+
+func TestConformanceSQLite(t *testing.T) {
+	setup := func(t *testing.T) (product.Repository, domain.Warehouse) {
+		db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "my_db.sqlite"))
+		assert.NoError(t, err)
+		assert.NoError(t, database.Migrate(db, database.SQLite))
+		t.Cleanup(func() { db.Close() })
+		_, err = db.Exec(`INSERT INTO warehouses(id, name, address, telephone, capacity) VALUES (1, 'Main Warehouse', '221 Baker Street', '4555666', 100)`)
+		assert.NoError(t, err)
+		return product.NewSQLiteRepository(db), mainWarehouse
+	}
+	producttest.RunRepositoryTests(t, setup)
+	producttest.RunSearchTests(t, setup)
+}
+
+// TestConformancePostgres vacia las tablas de POSTGRES_DSN antes de cada test. No se
+// usa txdb porque postgres aborta la transaccion despues del primer error
+func TestConformancePostgres(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN not set")
+	}
+	setup := func(t *testing.T) (product.Repository, domain.Warehouse) {
+		db, err := database.OpenPostgres(dsn)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		t.Cleanup(func() { db.Close() })
+		assert.NoError(t, database.Migrate(db, database.Postgres))
+		_, err = db.Exec(`TRUNCATE products, warehouses RESTART IDENTITY CASCADE;
+		INSERT INTO warehouses(name, address, telephone, capacity) VALUES ('Main Warehouse', '221 Baker Street', '4555666', 100)`)
+		assert.NoError(t, err)
+		return product.NewPostgresRepository(db), mainWarehouse
+	}
+	producttest.RunRepositoryTests(t, setup)
+	producttest.RunSearchTests(t, setup)
+}
+
+// TestConformanceMySQL corre cada test en una transaccion de txdb sobre MYSQL_DSN,
+// que debe tener cargado el warehouse 1 de los datos de prueba. No corre
+// RunSearchTests, el indice FULLTEXT no ve las filas sin confirmar de txdb
+func TestConformanceMySQL(t *testing.T) {
+	if os.Getenv("MYSQL_DSN") == "" {
+		t.Skip("MYSQL_DSN not set")
+	}
+	producttest.RunRepositoryTests(t, func(t *testing.T) (product.Repository, domain.Warehouse) {
+		db, err := sql.Open("txdb_mysql", t.Name())
+		assert.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return product.NewMySQLRepository(db), mainWarehouse
+	})
+}
@@ -0,0 +1,323 @@
+package product
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/query"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+const (
+	// DefaultLimit es la cantidad de productos por pagina cuando el pedido no la indica
+	DefaultLimit = 20
+	// MaxLimit es la mayor cantidad de productos por pagina
+	MaxLimit = 100
+)
+
+// productTable son las columnas de products que se pueden leer, filtrar y ordenar,
+// en el orden en que las lee scanProducts
+var productTable = query.Table{
+	Name:    "products",
+	Columns: []string{"id", "name", "quantity", "code_value", "is_published", "expiration", "price", "id_warehouse"},
+}
+
+// SortFields son los campos por los que se puede ordenar el listado, con el mismo
+// nombre que en el json
+var SortFields = productTable.Columns
+
+// Filter son los filtros del listado, los campos en nil o en cero no filtran. Los
+// rangos de precio y cantidad incluyen los extremos, las fechas no
+type Filter struct {
+	IsPublished   *bool
+	WarehouseId   int
+	MinPrice      *float64
+	MaxPrice      *float64
+	MinQuantity   *int
+	MaxQuantity   *int
+	ExpiresBefore domain.Date
+	ExpiresAfter  domain.Date
+}
+
+// SortField es un campo del orden del listado, Desc ordena de mayor a menor
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ListQuery es el pedido de una pagina del listado. La pagina se elige con Offset o
+// con los cursores After y Before, que son ids y solo se pueden usar ordenando por id
+type ListQuery struct {
+	Filter Filter
+	Sort   []SortField
+	Limit  int
+	Offset int
+	After  int
+	Before int
+}
+
+// Page es una pagina del listado. Total cuenta todos los productos que cumplen los
+// filtros, HasNext y HasPrev indican si hay productos despues y antes de la pagina
+type Page struct {
+	Products []domain.Product
+	Total    int
+	HasNext  bool
+	HasPrev  bool
+}
+
+// Validate revisa los parametros del listado
+func (q ListQuery) Validate() error {
+	var v domain.Validator
+	v.Check(q.Limit >= 1 && q.Limit <= MaxLimit, "limit", fmt.Sprintf("must be between 1 and %d", MaxLimit))
+	v.Check(q.Offset >= 0, "offset", "can't be negative")
+	v.Check(q.After >= 0, "after", "can't be negative")
+	v.Check(q.Before >= 0, "before", "can't be negative")
+	v.Check(q.After == 0 || q.Before == 0, "before", "can't be used with after")
+	v.Check(q.Offset == 0 || (q.After == 0 && q.Before == 0), "offset", "can't be used with after or before")
+	v.Check((q.After == 0 && q.Before == 0) || q.sortedByID(), "sort", "must be id or -id to use after or before")
+	seen := make(map[string]bool)
+	for _, s := range q.Sort {
+		v.Check(isSortField(s.Field), "sort", fmt.Sprintf("can't sort by %q", s.Field))
+		v.Check(!seen[s.Field], "sort", fmt.Sprintf("%q is repeated", s.Field))
+		seen[s.Field] = true
+	}
+	f := q.Filter
+	v.Check(f.MinPrice == nil || f.MaxPrice == nil || *f.MinPrice <= *f.MaxPrice, "price_min", "can't be greater than price_max")
+	v.Check(f.MinQuantity == nil || f.MaxQuantity == nil || *f.MinQuantity <= *f.MaxQuantity, "quantity_min", "can't be greater than quantity_max")
+	v.Check(f.ExpiresBefore.IsZero() || f.ExpiresAfter.IsZero() || f.ExpiresAfter.Before(f.ExpiresBefore.Time), "expiration_after", "must be before expiration_before")
+	return v.Err("invalid query")
+}
+
+// Keyset indica si la pagina se puede seguir con los cursores, es decir si esta
+// ordenada solo por id y no usa offset
+func (q ListQuery) Keyset() bool {
+	return q.Offset == 0 && q.sortedByID()
+}
+
+// sortedByID indica si el listado esta ordenado solo por id
+func (q ListQuery) sortedByID() bool {
+	return len(q.Sort) == 0 || (len(q.Sort) == 1 && q.Sort[0].Field == "id")
+}
+
+func isSortField(field string) bool {
+	return productTable.Has(field)
+}
+
+// cursor devuelve la condicion sobre el id del cursor, el operador es vacio sin
+// cursor. Con Before la pagina se busca en el orden inverso y reverse es true
+func (q ListQuery) cursor() (op query.Op, id int, reverse bool) {
+	desc := len(q.Sort) == 1 && q.Sort[0].Desc
+	switch {
+	case q.After > 0 && desc, q.Before > 0 && !desc:
+		op = query.Lt
+	case q.After > 0, q.Before > 0:
+		op = query.Gt
+	}
+	if q.Before > 0 {
+		return op, q.Before, true
+	}
+	return op, q.After, false
+}
+
+// orderBy devuelve el orden de la consulta, siempre termina en id para que el orden
+// sea el mismo entre paginas. Con Before el orden esta invertido
+func (q ListQuery) orderBy() []SortField {
+	_, _, reverse := q.cursor()
+	var order []SortField
+	byID := false
+	for _, s := range q.Sort {
+		order = append(order, SortField{Field: s.Field, Desc: s.Desc != reverse})
+		byID = byID || s.Field == "id"
+	}
+	if !byID {
+		order = append(order, SortField{Field: "id", Desc: reverse})
+	}
+	return order
+}
+
+// newPage arma la pagina con los productos de la consulta, que trae uno de mas para
+// saber si hay mas productos en la direccion del cursor
+func newPage(products []domain.Product, total int, q ListQuery) Page {
+	more := len(products) > q.Limit
+	if more {
+		products = products[:q.Limit]
+	}
+	page := Page{Products: append([]domain.Product{}, products...), Total: total}
+	_, _, reverse := q.cursor()
+	if reverse {
+		for i, j := 0, len(page.Products)-1; i < j; i, j = i+1, j-1 {
+			page.Products[i], page.Products[j] = page.Products[j], page.Products[i]
+		}
+		page.HasPrev = more
+		page.HasNext = true
+		return page
+	}
+	page.HasNext = more
+	page.HasPrev = q.Offset > 0 || q.After > 0
+	return page
+}
+
+// listProducts resuelve el listado en memoria, para los repositorios que no tienen
+// una base donde consultar
+func listProducts(products []domain.Product, q ListQuery) Page {
+	var matched []domain.Product
+	for _, p := range products {
+		if q.Filter.match(p) {
+			matched = append(matched, p)
+		}
+	}
+	total := len(matched)
+
+	order := q.orderBy()
+	sort.SliceStable(matched, func(i, j int) bool {
+		for _, s := range order {
+			if c := compareField(matched[i], matched[j], s.Field); c != 0 {
+				return c < 0 != s.Desc
+			}
+		}
+		return false
+	})
+
+	op, id, _ := q.cursor()
+	var page []domain.Product
+	for _, p := range matched {
+		if op == query.Gt && p.Id <= id || op == query.Lt && p.Id >= id {
+			continue
+		}
+		page = append(page, p)
+	}
+	if q.Offset >= len(page) {
+		page = nil
+	} else {
+		page = page[q.Offset:]
+	}
+	if len(page) > q.Limit+1 {
+		page = page[:q.Limit+1]
+	}
+	return newPage(page, total, q)
+}
+
+// match indica si el producto cumple los filtros
+func (f Filter) match(p domain.Product) bool {
+	return (f.IsPublished == nil || p.IsPublished == *f.IsPublished) &&
+		(f.WarehouseId == 0 || p.WarehouseId == f.WarehouseId) &&
+		(f.MinPrice == nil || p.Price >= *f.MinPrice) &&
+		(f.MaxPrice == nil || p.Price <= *f.MaxPrice) &&
+		(f.MinQuantity == nil || p.Quantity >= *f.MinQuantity) &&
+		(f.MaxQuantity == nil || p.Quantity <= *f.MaxQuantity) &&
+		(f.ExpiresBefore.IsZero() || p.Expiration.Before(f.ExpiresBefore.Time)) &&
+		(f.ExpiresAfter.IsZero() || p.Expiration.After(f.ExpiresAfter.Time))
+}
+
+// compareField compara el campo field de dos productos, devuelve -1, 0 o 1
+func compareField(a, b domain.Product, field string) int {
+	switch field {
+	case "name":
+		return strings.Compare(a.Name, b.Name)
+	case "code_value":
+		return strings.Compare(a.CodeValue, b.CodeValue)
+	case "quantity":
+		return compareInt(a.Quantity, b.Quantity)
+	case "id_warehouse":
+		return compareInt(a.WarehouseId, b.WarehouseId)
+	case "is_published":
+		return compareInt(boolInt(a.IsPublished), boolInt(b.IsPublished))
+	case "expiration":
+		return compareInt(int(a.Expiration.Unix()), int(b.Expiration.Unix()))
+	case "price":
+		switch {
+		case a.Price < b.Price:
+			return -1
+		case a.Price > b.Price:
+			return 1
+		}
+		return 0
+	default:
+		return compareInt(a.Id, b.Id)
+	}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// statement es una consulta con sus argumentos
+type statement struct {
+	sql  string
+	args []interface{}
+}
+
+// listSQL arma las consultas del listado para dialect: count cuenta los productos
+// que cumplen los filtros y list trae la pagina, con un producto de mas
+func listSQL(q ListQuery, dialect database.Dialect) (count, list statement, err error) {
+	b := query.New(dialect, productTable)
+	f := q.Filter
+	if f.IsPublished != nil {
+		b.Where("is_published", query.Eq, *f.IsPublished)
+	}
+	if f.WarehouseId != 0 {
+		b.Where("id_warehouse", query.Eq, f.WarehouseId)
+	}
+	if f.MinPrice != nil {
+		b.Where("price", query.Ge, *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		b.Where("price", query.Le, *f.MaxPrice)
+	}
+	if f.MinQuantity != nil {
+		b.Where("quantity", query.Ge, *f.MinQuantity)
+	}
+	if f.MaxQuantity != nil {
+		b.Where("quantity", query.Le, *f.MaxQuantity)
+	}
+	if !f.ExpiresBefore.IsZero() {
+		b.Where("expiration", query.Lt, f.ExpiresBefore)
+	}
+	if !f.ExpiresAfter.IsZero() {
+		b.Where("expiration", query.Gt, f.ExpiresAfter)
+	}
+	if count.sql, count.args, err = b.Count(); err != nil {
+		return statement{}, statement{}, err
+	}
+
+	if op, id, _ := q.cursor(); op != "" {
+		b.Where("id", op, id)
+	}
+	for _, s := range q.orderBy() {
+		b.OrderBy(s.Field, s.Desc)
+	}
+	if list.sql, list.args, err = b.Limit(q.Limit + 1).Offset(q.Offset).Select(); err != nil {
+		return statement{}, statement{}, err
+	}
+	return count, list, nil
+}
+
+// scanProducts lee todas las filas de una consulta de las columnas de productTable
+func scanProducts(rows *sql.Rows) ([]domain.Product, error) {
+	defer rows.Close()
+	var products []domain.Product
+	for rows.Next() {
+		var product domain.Product
+		if err := rows.Scan(&product.Id, &product.Name, &product.Quantity, &product.CodeValue, &product.IsPublished, &product.Expiration, &product.Price, &product.WarehouseId); err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
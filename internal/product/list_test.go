@@ -0,0 +1,99 @@
+package product
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/query"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListQuery_Validate(t *testing.T) {
+	minPrice, maxPrice := 10.0, 5.0
+
+	cases := []struct {
+		name string
+		q    ListQuery
+		exp  []domain.FieldError
+	}{
+		{"Success", ListQuery{Limit: 20, Sort: []SortField{{Field: "price", Desc: true}, {Field: "name"}}}, nil},
+		{"Success, cursor by id", ListQuery{Limit: 20, Sort: []SortField{{Field: "id", Desc: true}}, After: 10}, nil},
+		{"failed, limit", ListQuery{Limit: MaxLimit + 1}, []domain.FieldError{{Field: "limit", Message: "must be between 1 and 100"}}},
+		{"failed, cursor and offset", ListQuery{Limit: 20, Offset: 5, After: 10}, []domain.FieldError{{Field: "offset", Message: "can't be used with after or before"}}},
+		{"failed, both cursors", ListQuery{Limit: 20, After: 10, Before: 20}, []domain.FieldError{{Field: "before", Message: "can't be used with after"}}},
+		{"failed, cursor sorted by price", ListQuery{Limit: 20, Sort: []SortField{{Field: "price"}}, Before: 20}, []domain.FieldError{{Field: "sort", Message: "must be id or -id to use after or before"}}},
+		{"failed, unknown and repeated fields", ListQuery{Limit: 20, Sort: []SortField{{Field: "price"}, {Field: "color"}, {Field: "price", Desc: true}}}, []domain.FieldError{
+			{Field: "sort", Message: `can't sort by "color"`},
+			{Field: "sort", Message: `"price" is repeated`},
+		}},
+		{"failed, ranges", ListQuery{Limit: 20, Filter: Filter{MinPrice: &minPrice, MaxPrice: &maxPrice, ExpiresBefore: domain.NewDate(2022, 1, 1), ExpiresAfter: domain.NewDate(2022, 1, 1)}}, []domain.FieldError{
+			{Field: "price_min", Message: "can't be greater than price_max"},
+			{Field: "expiration_after", Message: "must be before expiration_before"},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			err := c.q.Validate()
+
+			// assert
+			if c.exp == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validation *domain.ValidationError
+			assert.True(t, errors.As(err, &validation))
+			assert.Equal(t, c.exp, validation.Fields)
+		})
+	}
+}
+
+func TestListSQL(t *testing.T) {
+	t.Run("Success, filters and sort", func(t *testing.T) {
+		// arrange
+		published, minPrice := true, 9.5
+		q := ListQuery{
+			Filter: Filter{IsPublished: &published, WarehouseId: 2, MinPrice: &minPrice, ExpiresBefore: domain.NewDate(2022, 1, 1)},
+			Sort:   []SortField{{Field: "price", Desc: true}},
+			Limit:  20,
+			Offset: 40,
+		}
+		where := `WHERE "is_published" = $1 AND "id_warehouse" = $2 AND "price" >= $3 AND "expiration" < $4`
+
+		// act
+		count, list, err := listSQL(q, database.Postgres)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT COUNT(*) FROM "products" `+where, count.sql)
+		assert.Equal(t, []interface{}{true, 2, 9.5, domain.NewDate(2022, 1, 1)}, count.args)
+		assert.Equal(t, `SELECT "id", "name", "quantity", "code_value", "is_published", "expiration", "price", "id_warehouse" FROM "products" `+where+
+			` ORDER BY "price" DESC, "id" LIMIT $5 OFFSET $6`, list.sql)
+		assert.Equal(t, []interface{}{true, 2, 9.5, domain.NewDate(2022, 1, 1), 21, 40}, list.args)
+	})
+
+	t.Run("Success, before cursor reverses the order", func(t *testing.T) {
+		// arrange
+		q := ListQuery{Limit: 10, Before: 30}
+
+		// act
+		count, list, err := listSQL(q, database.MySQL)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(*) FROM `products`", count.sql)
+		assert.Empty(t, count.args)
+		assert.Equal(t, "SELECT `id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse` FROM `products` WHERE `id` < ? ORDER BY `id` DESC LIMIT ?", list.sql)
+		assert.Equal(t, []interface{}{30, 11}, list.args)
+	})
+
+	t.Run("failed, unknown sort field", func(t *testing.T) {
+		// act
+		_, _, err := listSQL(ListQuery{Limit: 10, Sort: []SortField{{Field: "price; DROP TABLE products"}}}, database.SQLite)
+
+		// assert
+		assert.ErrorIs(t, err, query.ErrUnknownColumn)
+	})
+}
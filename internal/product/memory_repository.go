@@ -0,0 +1,112 @@
+package product
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+// memoryRepository struct definition
+type memoryRepository struct {
+	database *database.MemoryDB
+}
+
+// NewMemoryRepository constructor function
+func NewMemoryRepository(database *database.MemoryDB) Repository {
+	return &memoryRepository{database}
+}
+
+// memoryError maps a MemoryDB error onto the repository errors
+func memoryError(err error) error {
+	switch {
+	case errors.Is(err, database.ErrRowNotFound):
+		return ErrNotFound
+	case errors.Is(err, database.ErrUniqueViolation):
+		return ErrDuplicateEntry
+	case errors.Is(err, database.ErrForeignKeyViolation):
+		return ErrForeignKey
+	default:
+		return ErrInternal
+	}
+}
+
+// Create method to insert a new product
+func (repository *memoryRepository) Create(ctx context.Context, product domain.Product) (domain.Product, error) {
+	if product.Expiration.IsZero() {
+		return domain.Product{}, ErrParsingDate
+	}
+	stored, err := repository.database.InsertProduct(product)
+	if err != nil {
+		return domain.Product{}, memoryError(err)
+	}
+	product.Id = stored.Id
+	return product, nil
+}
+
+func (repository *memoryRepository) GetByCode(ctx context.Context, code string) (domain.Product, error) {
+	return productByCode(code, productsByCode(repository.database.Products(), []string{code}))
+}
+
+func (repository *memoryRepository) GetByCodes(ctx context.Context, codes []string) ([]domain.Product, error) {
+	return productsByCode(repository.database.Products(), codes), nil
+}
+
+func (repository *memoryRepository) GetAll(ctx context.Context) ([]domain.Product, error) {
+	return repository.database.Products(), nil
+}
+
+func (repository *memoryRepository) List(ctx context.Context, q ListQuery) (Page, error) {
+	return listProducts(repository.database.Products(), q), nil
+}
+
+func (repository *memoryRepository) Search(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	return searchProducts(repository.database.Products(), q), nil
+}
+
+func (repository *memoryRepository) FuzzySearch(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	return fuzzySearch(repository.database.Products(), q), nil
+}
+
+func (repository *memoryRepository) GetFullData(ctx context.Context, id int) (domain.ProductFull, error) {
+	product, err := repository.database.Product(id)
+	if err != nil {
+		return domain.ProductFull{}, memoryError(err)
+	}
+	warehouse, err := repository.database.Warehouse(product.WarehouseId)
+	if err != nil {
+		return domain.ProductFull{}, memoryError(err)
+	}
+	return domain.ProductFull{
+		Product:          product,
+		WarehouseName:    warehouse.Name,
+		WarehouseAddress: warehouse.Address,
+	}, nil
+}
+
+func (repository *memoryRepository) GetByID(ctx context.Context, id int) (domain.Product, error) {
+	product, err := repository.database.Product(id)
+	if err != nil {
+		return domain.Product{}, memoryError(err)
+	}
+	return product, nil
+}
+
+func (repository *memoryRepository) Update(ctx context.Context, id int, product domain.Product) (domain.Product, error) {
+	if product.Expiration.IsZero() {
+		return domain.Product{}, ErrParsingDate
+	}
+	product.Id = id
+	if err := repository.database.UpdateProduct(product); err != nil {
+		return domain.Product{}, memoryError(err)
+	}
+	return product, nil
+}
+
+func (repository *memoryRepository) Delete(ctx context.Context, id int) error {
+	if err := repository.database.DeleteProduct(id); err != nil {
+		return memoryError(err)
+	}
+	return nil
+}
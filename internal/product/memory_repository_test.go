@@ -0,0 +1,226 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMemoryDatabase crea una base en memoria con los mismos datos que espera txdb
+func newMemoryDatabase() *database.MemoryDB {
+	db := database.NewMemoryDB()
+	db.InsertWarehouse(domain.Warehouse{Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100})
+	db.InsertWarehouse(domain.Warehouse{Name: "SuperMarket", Address: "123 Main Street", Telephone: "555-555-5555", Capacity: 2222})
+	db.InsertProduct(domain.Product{Name: "Oil - Margarine", Quantity: 439, CodeValue: "S82254D", IsPublished: true, Expiration: domain.NewDate(2021, 12, 15), Price: 71.42, WarehouseId: 1})
+	db.InsertProduct(domain.Product{Name: "Pineapple - Canned, Rings", Quantity: 345, CodeValue: "M4637", IsPublished: true, Expiration: domain.NewDate(2021, 8, 9), Price: 352.79, WarehouseId: 2})
+	return db
+}
+
+func TestRepositoryMemory_GetAll(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		// act
+		pr, err := rp.GetAll(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, pr, 2)
+	})
+}
+
+func TestRepositoryMemory_GetByID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		exp := domain.Product{Id: 1, Name: "Oil - Margarine", Quantity: 439, CodeValue: "S82254D", IsPublished: true, Expiration: domain.NewDate(2021, 12, 15), Price: 71.42, WarehouseId: 1}
+
+		// act
+		pr, err := rp.GetByID(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		// act
+		pr, err := rp.GetByID(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryMemory_GetFullData(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		// act
+		pr, err := rp.GetFullData(context.Background(), 2)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "SuperMarket", pr.WarehouseName)
+		assert.Equal(t, "123 Main Street", pr.WarehouseAddress)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		// act
+		pr, err := rp.GetFullData(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryMemory_Create(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		product := domain.Product{Name: "Cookie - Oatmeal", Quantity: 130, CodeValue: "M7157", Expiration: domain.NewDate(2022, 1, 28), Price: 275.47, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+		exp := product
+		exp.Id = 3
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+	})
+
+	t.Run("failed, duplicate code value", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "S82254D", Expiration: domain.NewDate(2022, 1, 28), Price: 1, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrDuplicateEntry)
+		assert.Empty(t, pr)
+	})
+
+	t.Run("failed, warehouse does not exist", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "NEW1", Expiration: domain.NewDate(2022, 1, 28), Price: 1, WarehouseId: 100}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrForeignKey)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryMemory_Update(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		product := domain.Product{Name: "Oil - Olive", Quantity: 10, CodeValue: "S82254D", Expiration: domain.NewDate(2023, 12, 15), Price: 80, WarehouseId: 2}
+		exp := product
+		exp.Id = 1
+
+		// act
+		pr, err := rp.Update(context.Background(), 1, product)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "NEW1", Expiration: domain.NewDate(2023, 12, 15), Price: 1, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Update(context.Background(), 100, product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryMemory_Delete(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		// act
+		err := rp.Delete(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		// act
+		err := rp.Delete(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestRepositoryMemory_ConcurrentCreate(t *testing.T) {
+	t.Run("Success, code value stays unique", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+		const writers = 20
+
+		// act
+		var wg sync.WaitGroup
+		errs := make(chan error, writers)
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := rp.Create(context.Background(), domain.Product{Name: fmt.Sprintf("product %d", i), Quantity: 1, CodeValue: "SAME", Expiration: domain.NewDate(2022, 1, 28), Price: 1, WarehouseId: 1})
+				errs <- err
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		created := 0
+		for err := range errs {
+			if err == nil {
+				created++
+				continue
+			}
+			assert.ErrorIs(t, err, ErrDuplicateEntry)
+		}
+
+		// assert
+		assert.Equal(t, 1, created)
+	})
+}
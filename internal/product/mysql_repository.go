@@ -1,75 +1,49 @@
 package product
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"fmt"
-	"time"
 
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
 	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
-	"github.com/go-sql-driver/mysql"
 )
 
-// Define custom error variables
+// Errors of the repositories, the database ones are translated by dberr
 var (
-	ErrAccessDenied       = errors.New("access denied for user")
-	ErrNoDatabaseSelected = errors.New("no database selected")
-	ErrNotNullColumn      = errors.New("column cannot be null")
-	ErrUnknownColumn      = errors.New("unknown column")
-	ErrDuplicateEntry     = errors.New("duplicate entry")
-	ErrSyntaxError        = errors.New("syntax error")
-	ErrTableDoesNotExist  = errors.New("table does not exist")
+	ErrAccessDenied       = dberr.ErrAccessDenied
+	ErrNoDatabaseSelected = dberr.ErrNoDatabaseSelected
+	ErrNotNullColumn      = dberr.ErrNotNullColumn
+	ErrUnknownColumn      = dberr.ErrUnknownColumn
+	ErrDuplicateEntry     = dberr.ErrDuplicateEntry
+	ErrSyntaxError        = dberr.ErrSyntaxError
+	ErrTableDoesNotExist  = dberr.ErrTableDoesNotExist
+	ErrForeignKey         = dberr.ErrForeignKey
+	ErrDeadlock           = dberr.ErrDeadlock
 	ErrParsingDate        = errors.New("error parsing date")
 )
 
 // mySQLRepository struct definition
 type mySQLRepository struct {
-	database *sql.DB
+	database database.Conn
 }
 
 // NewMySQLRepository constructor function
-func NewMySQLRepository(database *sql.DB) Repository {
+func NewMySQLRepository(database database.Conn) Repository {
 	return &mySQLRepository{database}
 }
 
 // Create method to insert a new product into the products table
-func (repository *mySQLRepository) Create(product domain.Product) (domain.Product, error) {
-	parsedDate, err := time.Parse("02/01/2006", product.Expiration)
-	if err != nil {
+func (repository *mySQLRepository) Create(ctx context.Context, product domain.Product) (domain.Product, error) {
+	if product.Expiration.IsZero() {
 		return domain.Product{}, ErrParsingDate
 	}
-	formattedDate := parsedDate.Format("2006-01-02")
 
-	statement, err := repository.database.Prepare(`INSERT INTO products(name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES( ?, ?, ?, ?, ?, ?, ?)`)
+	result, err := repository.database.ExecContext(ctx, `INSERT INTO products(name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES( ?, ?, ?, ?, ?, ?, ?)`,
+		product.Name, product.Quantity, product.CodeValue, product.IsPublished, product.Expiration, product.Price, product.WarehouseId)
 	if err != nil {
-		return domain.Product{}, err
-	}
-	defer statement.Close()
-	var result sql.Result
-	result, err = statement.Exec(product.Name, product.Quantity, product.CodeValue, product.IsPublished, formattedDate, product.Price, product.WarehouseId)
-	if err != nil {
-		mysqlError, ok := err.(*mysql.MySQLError)
-		if !ok {
-			return domain.Product{}, ErrInternal
-		}
-		switch mysqlError.Number {
-		case 1044, 1045:
-			return domain.Product{}, ErrAccessDenied
-		case 1046:
-			return domain.Product{}, ErrNoDatabaseSelected
-		case 1048:
-			return domain.Product{}, ErrNotNullColumn
-		case 1054:
-			return domain.Product{}, ErrUnknownColumn
-		case 1062:
-			return domain.Product{}, ErrDuplicateEntry
-		case 1064:
-			return domain.Product{}, ErrSyntaxError
-		case 1146:
-			return domain.Product{}, ErrTableDoesNotExist
-		default:
-			return domain.Product{}, ErrInternal
-		}
+		return domain.Product{}, dberr.MySQL(err)
 	}
 	insertedId, err := result.LastInsertId()
 	if err != nil {
@@ -79,28 +53,11 @@ func (repository *mySQLRepository) Create(product domain.Product) (domain.Produc
 	return product, nil
 }
 
-func (repository *mySQLRepository) GetAll() ([]domain.Product, error) {
+func (repository *mySQLRepository) GetAll(ctx context.Context) ([]domain.Product, error) {
 	query := (`SELECT id, name, quantity, code_value, is_published, expiration, price, id_warehouse FROM products`)
-	rows, err := repository.database.Query(query)
+	rows, err := repository.database.QueryContext(ctx, query)
 	if err != nil {
-		mysqlError, ok := err.(*mysql.MySQLError)
-		if !ok {
-			return nil, ErrInternal
-		}
-		switch mysqlError.Number {
-		case 1044, 1045:
-			return nil, ErrAccessDenied
-		case 1046:
-			return nil, ErrNoDatabaseSelected
-		case 1054:
-			return nil, ErrUnknownColumn
-		case 1064:
-			return nil, ErrSyntaxError
-		case 1146:
-			return nil, ErrTableDoesNotExist
-		default:
-			return nil, ErrInternal
-		}
+		return nil, dberr.MySQL(err)
 	}
 	defer rows.Close()
 
@@ -108,165 +65,158 @@ func (repository *mySQLRepository) GetAll() ([]domain.Product, error) {
 	for rows.Next() {
 		var product domain.Product
 		if err := rows.Scan(&product.Id, &product.Name, &product.Quantity, &product.CodeValue, &product.IsPublished, &product.Expiration, &product.Price, &product.WarehouseId); err != nil {
-			return nil, ErrInternal
+			return nil, dberr.MySQL(err)
 		}
 		products = append(products, product)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, ErrInternal
+		return nil, dberr.MySQL(err)
 	}
 
 	return products, nil
 }
 
-func (repository *mySQLRepository) GetFullData(id int) (domain.ProductFull, error) {
+func (repository *mySQLRepository) List(ctx context.Context, q ListQuery) (Page, error) {
+	count, list, err := listSQL(q, database.MySQL)
+	if err != nil {
+		return Page{}, err
+	}
+	var total int
+	if err := repository.database.QueryRowContext(ctx, count.sql, count.args...).Scan(&total); err != nil {
+		return Page{}, dberr.MySQL(err)
+	}
+	rows, err := repository.database.QueryContext(ctx, list.sql, list.args...)
+	if err != nil {
+		return Page{}, dberr.MySQL(err)
+	}
+	products, err := scanProducts(rows)
+	if err != nil {
+		return Page{}, dberr.MySQL(err)
+	}
+	return newPage(products, total, q), nil
+}
+
+func (repository *mySQLRepository) Search(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	// the score is computed once, mysql reuses the MATCH of the WHERE clause
+	query := `SELECT id, name, quantity, code_value, is_published, expiration, price, id_warehouse, MATCH(name) AGAINST(? IN BOOLEAN MODE) AS score
+	FROM products WHERE MATCH(name) AGAINST(? IN BOOLEAN MODE) ORDER BY score DESC, id LIMIT ?`
+	against := booleanQuery(q.Terms)
+	rows, err := repository.database.QueryContext(ctx, query, against, against, q.Limit)
+	if err != nil {
+		return nil, dberr.MySQL(err)
+	}
+	hits, err := scanHits(rows)
+	if err != nil {
+		return nil, dberr.MySQL(err)
+	}
+	return hits, nil
+}
+
+func (repository *mySQLRepository) FuzzySearch(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	return fuzzyScan(q, database.MySQL, func(stmt statement) ([]domain.Product, error) {
+		rows, err := repository.database.QueryContext(ctx, stmt.sql, stmt.args...)
+		if err != nil {
+			return nil, dberr.MySQL(err)
+		}
+		products, err := scanProducts(rows)
+		if err != nil {
+			return nil, dberr.MySQL(err)
+		}
+		return products, nil
+	})
+}
+
+func (repository *mySQLRepository) GetFullData(ctx context.Context, id int) (domain.ProductFull, error) {
 	query := (`SELECT p.id, p.name, p.quantity, p.code_value, p.is_published, p.expiration, p.price, p.id_warehouse, w.name, w.address FROM products p 
 	INNER JOIN warehouses w ON p.id_warehouse = w.id WHERE p.id = ?`)
-	row := repository.database.QueryRow(query, id)
+	row := repository.database.QueryRowContext(ctx, query, id)
 	var productFull = domain.ProductFull{}
 	err := row.Scan(&productFull.Id, &productFull.Name, &productFull.Quantity, &productFull.CodeValue, &productFull.IsPublished, &productFull.Expiration, &productFull.Price, &productFull.WarehouseId, &productFull.WarehouseName, &productFull.WarehouseAddress)
 	if err != nil {
-		fmt.Println(err)
 		if err == sql.ErrNoRows {
 			return domain.ProductFull{}, ErrNotFound
 		}
 
-		mysqlError, ok := err.(*mysql.MySQLError)
-		if !ok {
-			return domain.ProductFull{}, ErrInternal
-		}
-		switch mysqlError.Number {
-		case 1044, 1045:
-			return domain.ProductFull{}, ErrAccessDenied
-		case 1046:
-			return domain.ProductFull{}, ErrNoDatabaseSelected
-		case 1054:
-			return domain.ProductFull{}, ErrUnknownColumn
-		case 1064:
-			return domain.ProductFull{}, ErrSyntaxError
-		case 1146:
-			return domain.ProductFull{}, ErrTableDoesNotExist
-		default:
-			return domain.ProductFull{}, ErrInternal
-		}
+		return domain.ProductFull{}, dberr.MySQL(err)
 	}
 	return productFull, nil
 }
 
-func (repository *mySQLRepository) GetByID(id int) (product domain.Product, err error) {
+func (repository *mySQLRepository) GetByID(ctx context.Context, id int) (product domain.Product, err error) {
 	query := `SELECT id, name, quantity, code_value, is_published, expiration, price, id_warehouse FROM products where id = ?`
-	row := repository.database.QueryRow(query, id)
+	row := repository.database.QueryRowContext(ctx, query, id)
 	err = row.Scan(&product.Id, &product.Name, &product.Quantity, &product.CodeValue, &product.IsPublished, &product.Expiration, &product.Price, &product.WarehouseId)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Product{}, ErrNotFound
 		}
 
-		mysqlError, ok := err.(*mysql.MySQLError)
-		if !ok {
-			return domain.Product{}, ErrInternal
-		}
-		switch mysqlError.Number {
-		case 1044, 1045:
-			return domain.Product{}, ErrAccessDenied
-		case 1046:
-			return domain.Product{}, ErrNoDatabaseSelected
-		case 1054:
-			return domain.Product{}, ErrUnknownColumn
-		case 1064:
-			return domain.Product{}, ErrSyntaxError
-		case 1146:
-			return domain.Product{}, ErrTableDoesNotExist
-		default:
-			return domain.Product{}, ErrInternal
-		}
+		return domain.Product{}, dberr.MySQL(err)
 	}
 
 	return product, nil
 }
 
-func (repository *mySQLRepository) Update(id int, product domain.Product) (domain.Product, error) {
-	parsedDate, err := time.Parse("02/01/2006", product.Expiration)
+func (repository *mySQLRepository) GetByCode(ctx context.Context, code string) (domain.Product, error) {
+	products, err := repository.GetByCodes(ctx, []string{code})
 	if err != nil {
-		return domain.Product{}, ErrParsingDate
+		return domain.Product{}, err
+	}
+	return productByCode(code, products)
+}
+
+func (repository *mySQLRepository) GetByCodes(ctx context.Context, codes []string) ([]domain.Product, error) {
+	stmt, err := codesSQL(codes, database.MySQL)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := repository.database.QueryContext(ctx, stmt.sql, stmt.args...)
+	if err != nil {
+		return nil, dberr.MySQL(err)
 	}
-	formattedDate := parsedDate.Format("2006-01-02")
-	statement, err := repository.database.Prepare(`UPDATE products SET name = ?, quantity = ?, code_value = ?, is_published = ?, expiration = ?, price = ?, id_warehouse = ? WHERE id = ?`)
+	products, err := scanProducts(rows)
 	if err != nil {
-		return domain.Product{}, ErrInternal
+		return nil, dberr.MySQL(err)
 	}
-	defer statement.Close()
-	result, err := statement.Exec(product.Name, product.Quantity, product.CodeValue, product.IsPublished, formattedDate, product.Price, product.WarehouseId, id)
+	return products, nil
+}
+
+func (repository *mySQLRepository) Update(ctx context.Context, id int, product domain.Product) (domain.Product, error) {
+	if product.Expiration.IsZero() {
+		return domain.Product{}, ErrParsingDate
+	}
+	result, err := repository.database.ExecContext(ctx, `UPDATE products SET name = ?, quantity = ?, code_value = ?, is_published = ?, expiration = ?, price = ?, id_warehouse = ? WHERE id = ?`,
+		product.Name, product.Quantity, product.CodeValue, product.IsPublished, product.Expiration, product.Price, product.WarehouseId, id)
 
 	if err != nil {
-		fmt.Println(err)
-		mysqlError, ok := err.(*mysql.MySQLError)
-		if !ok {
-			return domain.Product{}, ErrInternal
-		}
-		switch mysqlError.Number {
-		case 1044, 1045:
-			return domain.Product{}, ErrAccessDenied
-		case 1046:
-			return domain.Product{}, ErrNoDatabaseSelected
-		case 1048:
-			return domain.Product{}, ErrNotNullColumn
-		case 1054:
-			return domain.Product{}, ErrUnknownColumn
-		case 1062:
-			return domain.Product{}, ErrDuplicateEntry
-		case 1064:
-			return domain.Product{}, ErrSyntaxError
-		case 1146:
-			return domain.Product{}, ErrTableDoesNotExist
-		default:
-			return domain.Product{}, ErrInternal
-		}
+		return domain.Product{}, dberr.MySQL(err)
 	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return domain.Product{}, ErrInternal
+		return domain.Product{}, dberr.MySQL(err)
 	}
-	if rowsAffected == 0 && err != nil {
-		return domain.Product{}, ErrNotFound
+	// mysql only counts changed rows, so zero can also mean the values were the same
+	if rowsAffected == 0 {
+		if _, err := repository.GetByID(ctx, id); err != nil {
+			return domain.Product{}, err
+		}
 	}
 	product.Id = id
 	return product, nil
 }
 
-func (repository *mySQLRepository) Delete(id int) error {
-	statement, err := repository.database.Prepare(`DELETE FROM products WHERE id = ?`)
-	if err != nil {
-		return ErrInternal
-	}
-	defer statement.Close()
-	result, err := statement.Exec(id)
+func (repository *mySQLRepository) Delete(ctx context.Context, id int) error {
+	result, err := repository.database.ExecContext(ctx, `DELETE FROM products WHERE id = ?`,
+		id)
 
 	if err != nil {
-		mysqlError, ok := err.(*mysql.MySQLError)
-		if !ok {
-			return ErrInternal
-		}
-		switch mysqlError.Number {
-		case 1044, 1045:
-			return ErrAccessDenied
-		case 1046:
-			return ErrNoDatabaseSelected
-		case 1054:
-			return ErrUnknownColumn
-		case 1064:
-			return ErrSyntaxError
-		case 1146:
-			return ErrTableDoesNotExist
-		default:
-			return ErrInternal
-		}
+		return dberr.MySQL(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return ErrInternal
+		return dberr.MySQL(err)
 	}
 	if rowsAffected == 0 {
 		return ErrNotFound
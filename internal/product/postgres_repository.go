@@ -0,0 +1,198 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+// postgresRepository struct definition
+type postgresRepository struct {
+	database database.Conn
+}
+
+// NewPostgresRepository constructor function
+func NewPostgresRepository(database database.Conn) Repository {
+	return &postgresRepository{database}
+}
+
+// Create method to insert a new product into the products table
+func (repository *postgresRepository) Create(ctx context.Context, product domain.Product) (domain.Product, error) {
+	if product.Expiration.IsZero() {
+		return domain.Product{}, ErrParsingDate
+	}
+
+	// lib/pq does not support LastInsertId, the id comes back with RETURNING
+	row := repository.database.QueryRowContext(ctx, `INSERT INTO products(name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		product.Name, product.Quantity, product.CodeValue, product.IsPublished, product.Expiration, product.Price, product.WarehouseId)
+	if err := row.Scan(&product.Id); err != nil {
+		return domain.Product{}, dberr.Postgres(err)
+	}
+	return product, nil
+}
+
+func (repository *postgresRepository) GetAll(ctx context.Context) ([]domain.Product, error) {
+	query := `SELECT id, name, quantity, code_value, is_published, expiration, price, id_warehouse FROM products`
+	rows, err := repository.database.QueryContext(ctx, query)
+	if err != nil {
+		return nil, dberr.Postgres(err)
+	}
+	defer rows.Close()
+
+	var products []domain.Product
+	for rows.Next() {
+		var product domain.Product
+		if err := rows.Scan(&product.Id, &product.Name, &product.Quantity, &product.CodeValue, &product.IsPublished, &product.Expiration, &product.Price, &product.WarehouseId); err != nil {
+			return nil, dberr.Postgres(err)
+		}
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dberr.Postgres(err)
+	}
+
+	return products, nil
+}
+
+func (repository *postgresRepository) List(ctx context.Context, q ListQuery) (Page, error) {
+	count, list, err := listSQL(q, database.Postgres)
+	if err != nil {
+		return Page{}, err
+	}
+	var total int
+	if err := repository.database.QueryRowContext(ctx, count.sql, count.args...).Scan(&total); err != nil {
+		return Page{}, dberr.Postgres(err)
+	}
+	rows, err := repository.database.QueryContext(ctx, list.sql, list.args...)
+	if err != nil {
+		return Page{}, dberr.Postgres(err)
+	}
+	products, err := scanProducts(rows)
+	if err != nil {
+		return Page{}, dberr.Postgres(err)
+	}
+	return newPage(products, total, q), nil
+}
+
+func (repository *postgresRepository) Search(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	query := `SELECT id, name, quantity, code_value, is_published, expiration, price, id_warehouse, ts_rank(to_tsvector('simple', name), to_tsquery('simple', $1)) AS score
+	FROM products WHERE to_tsvector('simple', name) @@ to_tsquery('simple', $1) ORDER BY score DESC, id LIMIT $2`
+	rows, err := repository.database.QueryContext(ctx, query, tsQuery(q.Terms), q.Limit)
+	if err != nil {
+		return nil, dberr.Postgres(err)
+	}
+	hits, err := scanHits(rows)
+	if err != nil {
+		return nil, dberr.Postgres(err)
+	}
+	return hits, nil
+}
+
+func (repository *postgresRepository) FuzzySearch(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	return fuzzyScan(q, database.Postgres, func(stmt statement) ([]domain.Product, error) {
+		rows, err := repository.database.QueryContext(ctx, stmt.sql, stmt.args...)
+		if err != nil {
+			return nil, dberr.Postgres(err)
+		}
+		products, err := scanProducts(rows)
+		if err != nil {
+			return nil, dberr.Postgres(err)
+		}
+		return products, nil
+	})
+}
+
+func (repository *postgresRepository) GetFullData(ctx context.Context, id int) (domain.ProductFull, error) {
+	query := `SELECT p.id, p.name, p.quantity, p.code_value, p.is_published, p.expiration, p.price, p.id_warehouse, w.name, w.address FROM products p
+	INNER JOIN warehouses w ON p.id_warehouse = w.id WHERE p.id = $1`
+	row := repository.database.QueryRowContext(ctx, query, id)
+	var productFull = domain.ProductFull{}
+	err := row.Scan(&productFull.Id, &productFull.Name, &productFull.Quantity, &productFull.CodeValue, &productFull.IsPublished, &productFull.Expiration, &productFull.Price, &productFull.WarehouseId, &productFull.WarehouseName, &productFull.WarehouseAddress)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ProductFull{}, ErrNotFound
+		}
+		return domain.ProductFull{}, dberr.Postgres(err)
+	}
+	return productFull, nil
+}
+
+func (repository *postgresRepository) GetByID(ctx context.Context, id int) (product domain.Product, err error) {
+	query := `SELECT id, name, quantity, code_value, is_published, expiration, price, id_warehouse FROM products where id = $1`
+	row := repository.database.QueryRowContext(ctx, query, id)
+	err = row.Scan(&product.Id, &product.Name, &product.Quantity, &product.CodeValue, &product.IsPublished, &product.Expiration, &product.Price, &product.WarehouseId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Product{}, ErrNotFound
+		}
+		return domain.Product{}, dberr.Postgres(err)
+	}
+
+	return product, nil
+}
+
+func (repository *postgresRepository) GetByCode(ctx context.Context, code string) (domain.Product, error) {
+	products, err := repository.GetByCodes(ctx, []string{code})
+	if err != nil {
+		return domain.Product{}, err
+	}
+	return productByCode(code, products)
+}
+
+func (repository *postgresRepository) GetByCodes(ctx context.Context, codes []string) ([]domain.Product, error) {
+	stmt, err := codesSQL(codes, database.Postgres)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := repository.database.QueryContext(ctx, stmt.sql, stmt.args...)
+	if err != nil {
+		return nil, dberr.Postgres(err)
+	}
+	products, err := scanProducts(rows)
+	if err != nil {
+		return nil, dberr.Postgres(err)
+	}
+	return products, nil
+}
+
+func (repository *postgresRepository) Update(ctx context.Context, id int, product domain.Product) (domain.Product, error) {
+	if product.Expiration.IsZero() {
+		return domain.Product{}, ErrParsingDate
+	}
+	result, err := repository.database.ExecContext(ctx, `UPDATE products SET name = $1, quantity = $2, code_value = $3, is_published = $4, expiration = $5, price = $6, id_warehouse = $7 WHERE id = $8`,
+		product.Name, product.Quantity, product.CodeValue, product.IsPublished, product.Expiration, product.Price, product.WarehouseId, id)
+	if err != nil {
+		return domain.Product{}, dberr.Postgres(err)
+	}
+	// postgres counts matched rows, so zero really means there is no such product
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return domain.Product{}, dberr.Postgres(err)
+	}
+	if rowsAffected == 0 {
+		return domain.Product{}, ErrNotFound
+	}
+	product.Id = id
+	return product, nil
+}
+
+func (repository *postgresRepository) Delete(ctx context.Context, id int) error {
+	result, err := repository.database.ExecContext(ctx, `DELETE FROM products WHERE id = $1`, id)
+	if err != nil {
+		return dberr.Postgres(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dberr.Postgres(err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
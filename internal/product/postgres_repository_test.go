@@ -0,0 +1,212 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/DATA-DOG/go-txdb"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	if dsn := os.Getenv("POSTGRES_DSN"); dsn != "" {
+		txdb.Register("txdb_postgres", "postgres", dsn)
+	}
+}
+
+// newPostgresDatabase abre una transaccion de txdb sobre POSTGRES_DSN con los mismos
+// datos que espera txdb en mysql, se omite el test si no hay postgres configurado
+func newPostgresDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+	if os.Getenv("POSTGRES_DSN") == "" {
+		t.Skip("POSTGRES_DSN not set")
+	}
+	db, err := sql.Open("txdb_postgres", t.Name())
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	assert.NoError(t, database.Migrate(db, database.Postgres))
+	_, err = db.Exec(`TRUNCATE products, warehouses RESTART IDENTITY CASCADE;
+	INSERT INTO warehouses(name, address, telephone, capacity) VALUES
+		('Main Warehouse', '221 Baker Street', '4555666', 100),
+		('SuperMarket', '123 Main Street', '555-555-5555', 2222);
+	INSERT INTO products(name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES
+		('Oil - Margarine', 439, 'S82254D', TRUE, '2021-12-15', 71.42, 1),
+		('Pineapple - Canned, Rings', 345, 'M4637', TRUE, '2021-08-09', 352.79, 2)`)
+	assert.NoError(t, err)
+	return db
+}
+
+func TestRepositoryPostgres_GetAll(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		// act
+		pr, err := rp.GetAll(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, pr, 2)
+	})
+}
+
+func TestRepositoryPostgres_GetByID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		exp := domain.Product{Id: 1, Name: "Oil - Margarine", Quantity: 439, CodeValue: "S82254D", IsPublished: true, Expiration: domain.NewDate(2021, 12, 15), Price: 71.42, WarehouseId: 1}
+
+		// act
+		pr, err := rp.GetByID(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		// act
+		pr, err := rp.GetByID(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryPostgres_GetFullData(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		// act
+		pr, err := rp.GetFullData(context.Background(), 2)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "SuperMarket", pr.WarehouseName)
+		assert.Equal(t, "123 Main Street", pr.WarehouseAddress)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		// act
+		pr, err := rp.GetFullData(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryPostgres_Create(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		product := domain.Product{Name: "Cookie - Oatmeal", Quantity: 130, CodeValue: "M7157", Expiration: domain.NewDate(2022, 1, 28), Price: 275.47, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+		exp := product
+		exp.Id = 3
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+	})
+
+	t.Run("failed, duplicate code value", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "S82254D", Expiration: domain.NewDate(2022, 1, 28), Price: 1, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrDuplicateEntry)
+		assert.Empty(t, pr)
+	})
+
+	t.Run("failed, warehouse does not exist", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "NEW1", Expiration: domain.NewDate(2022, 1, 28), Price: 1, WarehouseId: 100}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrForeignKey)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryPostgres_Update(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		product := domain.Product{Name: "Oil - Olive", Quantity: 10, CodeValue: "S82254D", Expiration: domain.NewDate(2023, 12, 15), Price: 80, WarehouseId: 2}
+		exp := product
+		exp.Id = 1
+
+		// act
+		pr, err := rp.Update(context.Background(), 1, product)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "NEW1", Expiration: domain.NewDate(2023, 12, 15), Price: 1, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Update(context.Background(), 100, product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryPostgres_Delete(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		// act
+		err := rp.Delete(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		// act
+		err := rp.Delete(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,707 @@
+// Package producttest contiene la suite de conformidad que debe pasar toda
+// implementacion de product.Repository
+package producttest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/product"
+	"github.com/stretchr/testify/assert"
+)
+
+// missingID es un id que ningun backend deberia tener
+const missingID = 1 << 30
+
+// Setup crea un repositorio aislado para un test y devuelve un warehouse existente
+// al que pueden referenciar los productos. La base puede tener otros datos, la
+// suite solo asume los productos que crea
+type Setup func(t *testing.T) (product.Repository, domain.Warehouse)
+
+// newProduct devuelve un producto valido del warehouse con el code_value indicado
+func newProduct(warehouse domain.Warehouse, code string) domain.Product {
+	return domain.Product{Name: "Cookie - Oatmeal", Quantity: 130, CodeValue: code, IsPublished: true, Expiration: domain.NewDate(2022, 1, 28), Price: 275.47, WarehouseId: warehouse.Id}
+}
+
+// createListed crea cuatro productos para los tests del listado, con precios entre
+// 9000 y 9100 que no tiene ningun otro producto de las bases de los tests
+func createListed(t *testing.T, rp product.Repository, warehouse domain.Warehouse) []domain.Product {
+	t.Helper()
+	listed := []domain.Product{
+		{Name: "Oil - Margarine", Quantity: 10, CodeValue: "CONFORMANCE-1", IsPublished: true, Expiration: domain.NewDate(2030, 1, 10), Price: 9001, WarehouseId: warehouse.Id},
+		{Name: "Pineapple - Canned", Quantity: 20, CodeValue: "CONFORMANCE-2", IsPublished: false, Expiration: domain.NewDate(2030, 1, 20), Price: 9004, WarehouseId: warehouse.Id},
+		{Name: "Wine - Red Merlot", Quantity: 30, CodeValue: "CONFORMANCE-3", IsPublished: true, Expiration: domain.NewDate(2030, 1, 30), Price: 9002, WarehouseId: warehouse.Id},
+		{Name: "Cookie - Oatmeal", Quantity: 40, CodeValue: "CONFORMANCE-4", IsPublished: true, Expiration: domain.NewDate(2030, 2, 10), Price: 9003, WarehouseId: warehouse.Id},
+	}
+	for i := range listed {
+		created, err := rp.Create(context.Background(), listed[i])
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		listed[i] = created
+	}
+	return listed
+}
+
+// codes devuelve los code_value de los productos, en orden
+func codes(products []domain.Product) []string {
+	var codes []string
+	for _, p := range products {
+		codes = append(codes, p.CodeValue)
+	}
+	return codes
+}
+
+// assertSameProduct compara dos productos, todos los backends deben devolver la
+// misma fecha de expiracion que se guardo
+func assertSameProduct(t *testing.T, exp, got domain.Product) {
+	t.Helper()
+	assert.Equal(t, exp, got)
+	assert.Equal(t, exp.Expiration.String(), got.Expiration.String())
+}
+
+// RunRepositoryTests ejecuta la suite de conformidad contra los repositorios de setup
+func RunRepositoryTests(t *testing.T, setup Setup) {
+	ctx := context.Background()
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			product := newProduct(warehouse, "CONFORMANCE-1")
+
+			// act
+			pr, err := rp.Create(ctx, product)
+			stored, errGet := rp.GetByID(ctx, pr.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assert.NoError(t, errGet)
+			assert.NotZero(t, pr.Id)
+			exp := product
+			exp.Id = pr.Id
+			assert.Equal(t, exp, pr)
+			assertSameProduct(t, exp, stored)
+		})
+
+		t.Run("failed, duplicate code value", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			_, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-1"))
+			assert.NoError(t, err)
+
+			// act
+			pr, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-1"))
+
+			// assert
+			assert.ErrorIs(t, err, product.ErrDuplicateEntry)
+			assert.Empty(t, pr)
+		})
+
+		t.Run("failed, warehouse does not exist", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+
+			// act
+			pr, err := rp.Create(ctx, newProduct(domain.Warehouse{Id: missingID}, "CONFORMANCE-1"))
+
+			// assert
+			assert.ErrorIs(t, err, product.ErrForeignKey)
+			assert.Empty(t, pr)
+		})
+
+		t.Run("failed, missing expiration", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			p := newProduct(warehouse, "CONFORMANCE-1")
+			p.Expiration = domain.Date{}
+
+			// act
+			pr, err := rp.Create(ctx, p)
+
+			// assert
+			assert.ErrorIs(t, err, product.ErrParsingDate)
+			assert.Empty(t, pr)
+		})
+
+		t.Run("Success, concurrent duplicates create only one", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			const writers = 10
+
+			// act
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			created := 0
+			for i := 0; i < writers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-1")); err == nil {
+						mu.Lock()
+						created++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+
+			// assert
+			assert.Equal(t, 1, created)
+		})
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		t.Run("failed, not found", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+
+			// act
+			pr, err := rp.GetByID(ctx, missingID)
+
+			// assert
+			assert.ErrorIs(t, err, product.ErrNotFound)
+			assert.Empty(t, pr)
+		})
+	})
+
+	t.Run("GetByCode", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			listed := createListed(t, rp, warehouse)
+
+			// act
+			pr, err := rp.GetByCode(ctx, "CONFORMANCE-3")
+
+			// assert
+			assert.NoError(t, err)
+			assertSameProduct(t, listed[2], pr)
+		})
+
+		t.Run("Success, without case", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			listed := createListed(t, rp, warehouse)
+
+			// act
+			pr, err := rp.GetByCode(ctx, "conformance-3")
+
+			// assert
+			assert.NoError(t, err)
+			assertSameProduct(t, listed[2], pr)
+		})
+
+		t.Run("failed, not found", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+
+			// act
+			pr, err := rp.GetByCode(ctx, "CONFORMANCE-MISSING")
+
+			// assert
+			assert.ErrorIs(t, err, product.ErrNotFound)
+			assert.Empty(t, pr)
+		})
+	})
+
+	t.Run("GetByCodes", func(t *testing.T) {
+		t.Run("Success, only the existing codes", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			listed := createListed(t, rp, warehouse)
+
+			// act
+			products, err := rp.GetByCodes(ctx, []string{"CONFORMANCE-4", "CONFORMANCE-MISSING", "CONFORMANCE-1"})
+
+			// assert
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []string{"CONFORMANCE-1", "CONFORMANCE-4"}, codes(products))
+			for _, p := range products {
+				if p.CodeValue == "CONFORMANCE-1" {
+					assertSameProduct(t, listed[0], p)
+				}
+			}
+		})
+
+		t.Run("Success, mixed case codes", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			createListed(t, rp, warehouse)
+
+			// act
+			products, err := rp.GetByCodes(ctx, []string{"conformance-2", "Conformance-4"})
+
+			// assert
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []string{"CONFORMANCE-2", "CONFORMANCE-4"}, codes(products))
+		})
+
+		t.Run("Success, none found", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+
+			// act
+			products, err := rp.GetByCodes(ctx, []string{"CONFORMANCE-MISSING"})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Empty(t, products)
+		})
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			first, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-1"))
+			assert.NoError(t, err)
+			second, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-2"))
+			assert.NoError(t, err)
+
+			// act
+			products, err := rp.GetAll(ctx)
+
+			// assert
+			assert.NoError(t, err)
+			found := map[int]domain.Product{}
+			for _, p := range products {
+				found[p.Id] = p
+			}
+			assertSameProduct(t, first, found[first.Id])
+			assertSameProduct(t, second, found[second.Id])
+		})
+	})
+
+	t.Run("List", func(t *testing.T) {
+		// solo los productos de createListed tienen estos precios
+		minPrice, maxPrice := 9000.0, 9100.0
+		only := product.Filter{MinPrice: &minPrice, MaxPrice: &maxPrice}
+
+		t.Run("Success, filters", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			listed := createListed(t, rp, warehouse)
+			published := true
+			filter := only
+			filter.IsPublished = &published
+			filter.ExpiresAfter = domain.NewDate(2030, 1, 10)
+
+			// act
+			page, err := rp.List(ctx, product.ListQuery{Filter: filter, Limit: 10})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, 2, page.Total)
+			assert.Equal(t, []string{listed[2].CodeValue, listed[3].CodeValue}, codes(page.Products))
+			assertSameProduct(t, listed[2], page.Products[0])
+			assert.False(t, page.HasNext)
+			assert.False(t, page.HasPrev)
+		})
+
+		t.Run("Success, quantity range", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			listed := createListed(t, rp, warehouse)
+			minQuantity, maxQuantity := 20, 30
+			filter := only
+			filter.MinQuantity, filter.MaxQuantity = &minQuantity, &maxQuantity
+			filter.WarehouseId = warehouse.Id
+
+			// act
+			page, err := rp.List(ctx, product.ListQuery{Filter: filter, Limit: 10})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, []string{listed[1].CodeValue, listed[2].CodeValue}, codes(page.Products))
+		})
+
+		t.Run("Success, sort", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			listed := createListed(t, rp, warehouse)
+
+			// act
+			page, err := rp.List(ctx, product.ListQuery{Filter: only, Sort: []product.SortField{{Field: "price", Desc: true}}, Limit: 10})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, []string{listed[1].CodeValue, listed[3].CodeValue, listed[2].CodeValue, listed[0].CodeValue}, codes(page.Products))
+		})
+
+		t.Run("Success, offset", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			listed := createListed(t, rp, warehouse)
+			sortByPrice := []product.SortField{{Field: "price"}}
+
+			// act
+			first, errFirst := rp.List(ctx, product.ListQuery{Filter: only, Sort: sortByPrice, Limit: 3})
+			second, errSecond := rp.List(ctx, product.ListQuery{Filter: only, Sort: sortByPrice, Limit: 3, Offset: 3})
+
+			// assert
+			assert.NoError(t, errFirst)
+			assert.NoError(t, errSecond)
+			assert.Equal(t, []string{listed[0].CodeValue, listed[2].CodeValue, listed[3].CodeValue}, codes(first.Products))
+			assert.Equal(t, 4, first.Total)
+			assert.True(t, first.HasNext)
+			assert.False(t, first.HasPrev)
+			assert.Equal(t, []string{listed[1].CodeValue}, codes(second.Products))
+			assert.Equal(t, 4, second.Total)
+			assert.False(t, second.HasNext)
+			assert.True(t, second.HasPrev)
+		})
+
+		t.Run("Success, cursor", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			listed := createListed(t, rp, warehouse)
+
+			// act
+			first, errFirst := rp.List(ctx, product.ListQuery{Filter: only, Limit: 2})
+			next, errNext := rp.List(ctx, product.ListQuery{Filter: only, Limit: 2, After: listed[1].Id})
+			prev, errPrev := rp.List(ctx, product.ListQuery{Filter: only, Limit: 2, Before: listed[2].Id})
+
+			// assert
+			assert.NoError(t, errFirst)
+			assert.NoError(t, errNext)
+			assert.NoError(t, errPrev)
+			assert.Equal(t, []string{listed[0].CodeValue, listed[1].CodeValue}, codes(first.Products))
+			assert.True(t, first.HasNext)
+			assert.Equal(t, []string{listed[2].CodeValue, listed[3].CodeValue}, codes(next.Products))
+			assert.False(t, next.HasNext)
+			assert.True(t, next.HasPrev)
+			assert.Equal(t, 4, next.Total)
+			assert.Equal(t, []string{listed[0].CodeValue, listed[1].CodeValue}, codes(prev.Products))
+			assert.False(t, prev.HasPrev)
+			assert.True(t, prev.HasNext)
+		})
+
+		t.Run("Success, cursor descending", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			listed := createListed(t, rp, warehouse)
+
+			// act
+			page, err := rp.List(ctx, product.ListQuery{Filter: only, Sort: []product.SortField{{Field: "id", Desc: true}}, Limit: 2, After: listed[3].Id})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, []string{listed[2].CodeValue, listed[1].CodeValue}, codes(page.Products))
+			assert.True(t, page.HasNext)
+		})
+
+		t.Run("Success, empty", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+
+			// act
+			page, err := rp.List(ctx, product.ListQuery{Filter: only, Limit: 10})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, product.Page{Products: []domain.Product{}}, page)
+		})
+	})
+
+	t.Run("GetFullData", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			created, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-1"))
+			assert.NoError(t, err)
+
+			// act
+			pr, err := rp.GetFullData(ctx, created.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assertSameProduct(t, created, pr.Product)
+			assert.Equal(t, warehouse.Name, pr.WarehouseName)
+			assert.Equal(t, warehouse.Address, pr.WarehouseAddress)
+		})
+
+		t.Run("failed, not found", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+
+			// act
+			pr, err := rp.GetFullData(ctx, missingID)
+
+			// assert
+			assert.ErrorIs(t, err, product.ErrNotFound)
+			assert.Empty(t, pr)
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			created, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-1"))
+			assert.NoError(t, err)
+			update := domain.Product{Name: "Oil - Olive", Quantity: 10, CodeValue: "CONFORMANCE-2", Expiration: domain.NewDate(2023, 12, 15), Price: 80, WarehouseId: warehouse.Id}
+
+			// act
+			pr, err := rp.Update(ctx, created.Id, update)
+			stored, errGet := rp.GetByID(ctx, created.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assert.NoError(t, errGet)
+			exp := update
+			exp.Id = created.Id
+			assert.Equal(t, exp, pr)
+			assertSameProduct(t, exp, stored)
+		})
+
+		t.Run("Success, same values", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			created, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-1"))
+			assert.NoError(t, err)
+
+			// act
+			pr, err := rp.Update(ctx, created.Id, created)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, created, pr)
+		})
+
+		t.Run("Success, product read back", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			created, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-1"))
+			assert.NoError(t, err)
+			stored, err := rp.GetByID(ctx, created.Id)
+			assert.NoError(t, err)
+
+			// act
+			pr, err := rp.Update(ctx, created.Id, stored)
+			again, errGet := rp.GetByID(ctx, created.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assert.NoError(t, errGet)
+			assertSameProduct(t, stored, pr)
+			assertSameProduct(t, stored, again)
+		})
+
+		t.Run("failed, not found", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+
+			// act
+			pr, err := rp.Update(ctx, missingID, newProduct(warehouse, "CONFORMANCE-1"))
+
+			// assert
+			assert.ErrorIs(t, err, product.ErrNotFound)
+			assert.Empty(t, pr)
+		})
+
+		t.Run("failed, duplicate code value", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			_, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-1"))
+			assert.NoError(t, err)
+			second, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-2"))
+			assert.NoError(t, err)
+
+			// act
+			pr, err := rp.Update(ctx, second.Id, newProduct(warehouse, "CONFORMANCE-1"))
+
+			// assert
+			assert.ErrorIs(t, err, product.ErrDuplicateEntry)
+			assert.Empty(t, pr)
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			created, err := rp.Create(ctx, newProduct(warehouse, "CONFORMANCE-1"))
+			assert.NoError(t, err)
+
+			// act
+			err = rp.Delete(ctx, created.Id)
+			_, errGet := rp.GetByID(ctx, created.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assert.ErrorIs(t, errGet, product.ErrNotFound)
+		})
+
+		t.Run("failed, not found", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+
+			// act
+			err := rp.Delete(ctx, missingID)
+
+			// assert
+			assert.ErrorIs(t, err, product.ErrNotFound)
+		})
+	})
+}
+
+// RunSearchTests ejecuta la suite de la busqueda por nombre. Va aparte de
+// RunRepositoryTests porque el indice FULLTEXT de mysql no ve las filas de una
+// transaccion sin confirmar, y los tests de mysql corren dentro de una con txdb
+func RunSearchTests(t *testing.T, setup Setup) {
+	ctx := context.Background()
+
+	// createNamed crea un producto por nombre, con nombres que no tiene ninguna base
+	createNamed := func(t *testing.T, rp product.Repository, warehouse domain.Warehouse, names ...string) []domain.Product {
+		t.Helper()
+		var created []domain.Product
+		for i, name := range names {
+			p := newProduct(warehouse, fmt.Sprintf("CONFORMANCE-%d", i+1))
+			p.Name = name
+			pr, err := rp.Create(ctx, p)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			created = append(created, pr)
+		}
+		return created
+	}
+
+	t.Run("Search", func(t *testing.T) {
+		t.Run("Success, prefix of any word", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			created := createNamed(t, rp, warehouse, "Zucchini Bread", "Carrot Cake", "Loaf - Zucchini")
+
+			// act
+			hits, err := rp.Search(ctx, product.SearchQuery{Terms: []string{"zucch"}, Limit: 10})
+
+			// assert
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []string{created[0].CodeValue, created[2].CodeValue}, hitCodes(hits))
+			for _, hit := range hits {
+				assert.Greater(t, hit.Score, 0.0)
+			}
+		})
+
+		t.Run("Success, every word is required", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			created := createNamed(t, rp, warehouse, "Zucchini Bread", "Zucchini Soup", "Bread - Rye Zucchini")
+
+			// act
+			hits, err := rp.Search(ctx, product.SearchQuery{Terms: []string{"bread", "zucchini"}, Limit: 10})
+
+			// assert
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []string{created[0].CodeValue, created[2].CodeValue}, hitCodes(hits))
+			assertSameProduct(t, created[0], hitProduct(hits, created[0].CodeValue))
+		})
+
+		t.Run("Success, ordered by score and limited", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			createNamed(t, rp, warehouse, "Zucchini", "Zucchini Zucchini Bread", "Zucchini Soup", "Zucchinis")
+
+			// act
+			hits, err := rp.Search(ctx, product.SearchQuery{Terms: []string{"zucchini"}, Limit: 2})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Len(t, hits, 2)
+			for i := 1; i < len(hits); i++ {
+				assert.GreaterOrEqual(t, hits[i-1].Score, hits[i].Score)
+			}
+		})
+
+		t.Run("Success, follows updates and deletes", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			created := createNamed(t, rp, warehouse, "Zucchini Bread", "Quince Jam")
+			renamed := created[0]
+			renamed.Name = "Pumpkin Bread"
+			_, err := rp.Update(ctx, renamed.Id, renamed)
+			assert.NoError(t, err)
+			assert.NoError(t, rp.Delete(ctx, created[1].Id))
+
+			// act
+			old, errOld := rp.Search(ctx, product.SearchQuery{Terms: []string{"zucchini"}, Limit: 10})
+			current, errCurrent := rp.Search(ctx, product.SearchQuery{Terms: []string{"pumpkin"}, Limit: 10})
+			deleted, errDeleted := rp.Search(ctx, product.SearchQuery{Terms: []string{"quince"}, Limit: 10})
+
+			// assert
+			assert.NoError(t, errOld)
+			assert.NoError(t, errCurrent)
+			assert.NoError(t, errDeleted)
+			assert.Empty(t, old)
+			assert.Equal(t, []string{renamed.CodeValue}, hitCodes(current))
+			assert.Empty(t, deleted)
+		})
+
+		t.Run("Success, no match", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			createNamed(t, rp, warehouse, "Zucchini Bread")
+
+			// act
+			hits, err := rp.Search(ctx, product.SearchQuery{Terms: []string{"bread", "quince"}, Limit: 10})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Empty(t, hits)
+		})
+	})
+
+	t.Run("FuzzySearch", func(t *testing.T) {
+		t.Run("Success, words with typos", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			created := createNamed(t, rp, warehouse, "ZUCCHINI Bread", "Carrot Cake", "Oil - Margarine")
+
+			// act
+			hits, err := rp.FuzzySearch(ctx, product.SearchQuery{Terms: []string{"zuchini", "bred"}, Limit: 10})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, []string{created[0].CodeValue}, hitCodes(hits))
+			assert.True(t, hits[0].Fuzzy)
+			assertSameProduct(t, created[0], hits[0].Product)
+		})
+
+		t.Run("Success, no match", func(t *testing.T) {
+			// arrange
+			rp, warehouse := setup(t)
+			createNamed(t, rp, warehouse, "Zucchini Bread")
+
+			// act
+			hits, err := rp.FuzzySearch(ctx, product.SearchQuery{Terms: []string{"quince"}, Limit: 10})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Empty(t, hits)
+		})
+	})
+}
+
+// hitCodes devuelve los code_value de los resultados, en orden
+func hitCodes(hits []product.SearchHit) []string {
+	var codes []string
+	for _, hit := range hits {
+		codes = append(codes, hit.CodeValue)
+	}
+	return codes
+}
+
+// hitProduct devuelve el producto del resultado con code_value code
+func hitProduct(hits []product.SearchHit, code string) domain.Product {
+	for _, hit := range hits {
+		if hit.CodeValue == code {
+			return hit.Product
+		}
+	}
+	return domain.Product{}
+}
@@ -1,6 +1,7 @@
 package product
 
 import (
+	"context"
 	"errors"
 
 	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
@@ -9,17 +10,30 @@ import (
 
 type Repository interface {
 	// GetByID busca un producto por su id
-	GetByID(id int) (domain.Product, error)
+	GetByID(ctx context.Context, id int) (domain.Product, error)
+	// GetByCode busca un producto por su code_value
+	GetByCode(ctx context.Context, code string) (domain.Product, error)
+	// GetByCodes busca los productos con alguno de los codes, en cualquier orden, los
+	// codigos que no existen no son un error
+	GetByCodes(ctx context.Context, codes []string) ([]domain.Product, error)
 	// GetAll busca todos los productos
-	GetAll() ([]domain.Product, error)
+	GetAll(ctx context.Context) ([]domain.Product, error)
+	// List busca una pagina de los productos que cumplen los filtros de q
+	List(ctx context.Context, q ListQuery) (Page, error)
+	// Search busca los productos cuyo nombre tiene todas las palabras de q, como
+	// palabra completa o como comienzo de una palabra, ordenados por relevancia
+	Search(ctx context.Context, q SearchQuery) ([]SearchHit, error)
+	// FuzzySearch busca los productos cuyo nombre tiene cada palabra de q con pocos
+	// errores de tipeo. Las bases solo comparan una cantidad acotada de candidatos
+	FuzzySearch(ctx context.Context, q SearchQuery) ([]SearchHit, error)
 	// GetAll busca todos los productos y agrega datos de warehouse
-	GetFullData(id int) (domain.ProductFull, error)
+	GetFullData(ctx context.Context, id int) (domain.ProductFull, error)
 	// Create agrega un nuevo producto
-	Create(p domain.Product) (domain.Product, error)
+	Create(ctx context.Context, p domain.Product) (domain.Product, error)
 	// Update actualiza un producto
-	Update(id int, p domain.Product) (domain.Product, error)
+	Update(ctx context.Context, id int, p domain.Product) (domain.Product, error)
 	// Delete elimina un producto
-	Delete(id int) error
+	Delete(ctx context.Context, id int) error
 }
 
 type repository struct {
@@ -31,51 +45,116 @@ func NewRepository(storage store.StoreInterface) Repository {
 	return &repository{storage}
 }
 
-func (r *repository) GetByID(id int) (domain.Product, error) {
+// storageError traduce los errores del store a los errores del repositorio
+func storageError(err error) error {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return ErrNotFound
+	case errors.Is(err, store.ErrDuplicateCode):
+		return ErrDuplicateEntry
+	case errors.Is(err, store.ErrWarehouseNotFound):
+		return ErrForeignKey
+	default:
+		return ErrInternal
+	}
+}
+
+func (r *repository) GetByID(ctx context.Context, id int) (domain.Product, error) {
 	product, err := r.storage.Read(id)
 	if err != nil {
-		return domain.Product{}, errors.New("product not found")
+		return domain.Product{}, storageError(err)
 	}
 	return product, nil
 
 }
 
-// Only implemented in mysql_repository
-func (r *repository) GetAll() ([]domain.Product, error) {
-	return []domain.Product{}, nil
+func (r *repository) GetByCode(ctx context.Context, code string) (domain.Product, error) {
+	products, err := r.storage.ReadAll()
+	if err != nil {
+		return domain.Product{}, ErrInternal
+	}
+	return productByCode(code, productsByCode(products, []string{code}))
 }
 
-// Only implemented in mysql_repository
-func (r *repository) GetFullData(id int) (domain.ProductFull, error) {
-	return domain.ProductFull{}, nil
+func (r *repository) GetByCodes(ctx context.Context, codes []string) ([]domain.Product, error) {
+	products, err := r.storage.ReadAll()
+	if err != nil {
+		return nil, ErrInternal
+	}
+	return productsByCode(products, codes), nil
 }
 
-func (r *repository) Create(p domain.Product) (domain.Product, error) {
-	if !r.storage.Exists(p.CodeValue) {
-		return domain.Product{}, errors.New("code value already exists")
+func (r *repository) GetAll(ctx context.Context) ([]domain.Product, error) {
+	products, err := r.storage.ReadAll()
+	if err != nil {
+		return nil, ErrInternal
 	}
-	err := r.storage.Create(p)
+	return products, nil
+}
+
+func (r *repository) List(ctx context.Context, q ListQuery) (Page, error) {
+	products, err := r.storage.ReadAll()
 	if err != nil {
-		return domain.Product{}, errors.New("error creating product")
+		return Page{}, ErrInternal
 	}
-	return p, nil
+	return listProducts(products, q), nil
+}
+
+func (r *repository) Search(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	products, err := r.storage.ReadAll()
+	if err != nil {
+		return nil, ErrInternal
+	}
+	return searchProducts(products, q), nil
+}
+
+func (r *repository) FuzzySearch(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	products, err := r.storage.ReadAll()
+	if err != nil {
+		return nil, ErrInternal
+	}
+	return fuzzySearch(products, q), nil
+}
+
+func (r *repository) GetFullData(ctx context.Context, id int) (domain.ProductFull, error) {
+	productFull, err := r.storage.ReadFull(id)
+	if err != nil {
+		// igual que el INNER JOIN de mysql, un producto sin warehouse no se encuentra
+		if errors.Is(err, store.ErrNotFound) || errors.Is(err, store.ErrWarehouseNotFound) {
+			return domain.ProductFull{}, ErrNotFound
+		}
+		return domain.ProductFull{}, ErrInternal
+	}
+	return productFull, nil
+}
+
+func (r *repository) Create(ctx context.Context, p domain.Product) (domain.Product, error) {
+	if p.Expiration.IsZero() {
+		return domain.Product{}, ErrParsingDate
+	}
+	created, err := r.storage.Create(p)
+	if err != nil {
+		return domain.Product{}, storageError(err)
+	}
+	return created, nil
 }
 
-func (r *repository) Delete(id int) error {
+func (r *repository) Delete(ctx context.Context, id int) error {
 	err := r.storage.Delete(id)
 	if err != nil {
-		return err
+		return storageError(err)
 	}
 	return nil
 }
 
-func (r *repository) Update(id int, p domain.Product) (domain.Product, error) {
-	if !r.storage.Exists(p.CodeValue) {
-		return domain.Product{}, errors.New("code value already exists")
+func (r *repository) Update(ctx context.Context, id int, p domain.Product) (domain.Product, error) {
+	if p.Expiration.IsZero() {
+		return domain.Product{}, ErrParsingDate
 	}
+	p.Id = id
 	err := r.storage.Update(p)
 	if err != nil {
-		return domain.Product{}, errors.New("error updating product")
+		return domain.Product{}, storageError(err)
 	}
 	return p, nil
 }
@@ -0,0 +1,240 @@
+package product
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/bootcamp-go/consignas-go-db.git/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	productsFixture = `[
+		{"id":1,"name":"Oil - Margarine","quantity":439,"code_value":"S82254D","is_published":true,"expiration":"15/12/2021","price":71.42,"id_warehouse":1},
+		{"id":2,"name":"Pineapple - Canned, Rings","quantity":345,"code_value":"M4637","is_published":true,"expiration":"09/08/2021","price":352.79,"id_warehouse":2},
+		{"id":3,"name":"Wine - Red Oakridge Merlot","quantity":367,"code_value":"T65812","is_published":false,"expiration":"24/05/2021","price":179.23,"id_warehouse":9}
+	]`
+	warehousesFixture = `[
+		{"id":1,"name":"Main Warehouse","address":"221 Baker Street","telephone":"4555666","capacity":100},
+		{"id":2,"name":"SuperMarket","address":"123 Main Street","telephone":"555-555-5555","capacity":2222}
+	]`
+)
+
+// newJsonRepository crea un repositorio sobre una copia temporal de los fixtures
+func newJsonRepository(t *testing.T) Repository {
+	t.Helper()
+	dir := t.TempDir()
+	productsPath := filepath.Join(dir, "products.json")
+	warehousesPath := filepath.Join(dir, "warehouses.json")
+	assert.NoError(t, os.WriteFile(productsPath, []byte(productsFixture), 0644))
+	assert.NoError(t, os.WriteFile(warehousesPath, []byte(warehousesFixture), 0644))
+	return NewRepository(store.NewJsonStore(productsPath, warehousesPath))
+}
+
+func TestRepositoryJSON_GetAll(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		// act
+		pr, err := rp.GetAll(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, pr, 3)
+		assert.Equal(t, "S82254D", pr[0].CodeValue)
+	})
+}
+
+func TestRepositoryJSON_GetByID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		exp := domain.Product{Id: 2, Name: "Pineapple - Canned, Rings", Quantity: 345, CodeValue: "M4637", IsPublished: true, Expiration: domain.NewDate(2021, 8, 9), Price: 352.79, WarehouseId: 2}
+
+		// act
+		pr, err := rp.GetByID(context.Background(), 2)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		// act
+		pr, err := rp.GetByID(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryJSON_GetFullData(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		// act
+		pr, err := rp.GetFullData(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, pr.Id)
+		assert.Equal(t, "Main Warehouse", pr.WarehouseName)
+		assert.Equal(t, "221 Baker Street", pr.WarehouseAddress)
+	})
+
+	t.Run("failed, product not found", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		// act
+		pr, err := rp.GetFullData(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+
+	t.Run("failed, warehouse not found", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		// act
+		pr, err := rp.GetFullData(context.Background(), 3)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryJSON_Create(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		product := domain.Product{Name: "Cookie - Oatmeal", Quantity: 130, CodeValue: "M7157", Expiration: domain.NewDate(2022, 1, 28), Price: 275.47, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+		exp := product
+		exp.Id = 4
+		stored, _ := rp.GetByID(context.Background(), pr.Id)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+		assert.Equal(t, exp, stored)
+	})
+
+	t.Run("failed, duplicate code value", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "S82254D", Expiration: domain.NewDate(2022, 1, 28), Price: 1, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrDuplicateEntry)
+		assert.Empty(t, pr)
+	})
+
+	t.Run("failed, missing expiration", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "NEW1", Price: 1, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrParsingDate)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryJSON_Update(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		product := domain.Product{Name: "Oil - Olive", Quantity: 10, CodeValue: "S82254D", Expiration: domain.NewDate(2023, 12, 15), Price: 80, WarehouseId: 2}
+		exp := product
+		exp.Id = 1
+
+		// act
+		pr, err := rp.Update(context.Background(), 1, product)
+		stored, _ := rp.GetByID(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+		assert.Equal(t, exp, stored)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "NEW1", Expiration: domain.NewDate(2023, 12, 15), Price: 1, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Update(context.Background(), 100, product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+
+	t.Run("failed, duplicate code value", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "M4637", Expiration: domain.NewDate(2023, 12, 15), Price: 1, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Update(context.Background(), 1, product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrDuplicateEntry)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositoryJSON_Delete(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		// act
+		err := rp.Delete(context.Background(), 1)
+		_, errGet := rp.GetByID(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.ErrorIs(t, errGet, ErrNotFound)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := newJsonRepository(t)
+
+		// act
+		err := rp.Delete(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
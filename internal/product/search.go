@@ -0,0 +1,369 @@
+package product
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/query"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+const (
+	// MaxSearchTerms es la mayor cantidad de palabras de una busqueda
+	MaxSearchTerms = 8
+	// FuzzyMaxTerms es la mayor cantidad de palabras de una busqueda que tolera
+	// errores de tipeo
+	FuzzyMaxTerms = 2
+	// fuzzyMinLength es el largo minimo de una palabra para buscarla con errores, con
+	// menos letras casi cualquier palabra esta a un error de distancia
+	fuzzyMinLength = 3
+	// fuzzyBatch es la cantidad de candidatos que las bases leen por consulta en una
+	// busqueda con errores, la tabla se recorre en tandas para no tenerla en memoria
+	fuzzyBatch = 1000
+	// fuzzyMinPiece es el largo minimo de los pedazos de una palabra para usarlos como
+	// filtro, un pedazo mas corto esta en casi cualquier nombre y no descarta nada
+	fuzzyMinPiece = 3
+)
+
+// SearchQuery es una busqueda de productos por nombre. Terms son las palabras en
+// minusculas y cada una tiene que ser el comienzo de alguna palabra del nombre
+type SearchQuery struct {
+	Terms []string
+	Limit int
+}
+
+// SearchHit es un producto encontrado. Score ordena los resultados y solo sirve para
+// compararlos dentro de la misma busqueda, Highlight es el nombre escapado como html
+// con las palabras encontradas entre <mark> y </mark>. Fuzzy indica que el producto
+// se encontro tolerando errores de tipeo
+type SearchHit struct {
+	domain.Product
+	Score     float64 `json:"score"`
+	Highlight string  `json:"highlight"`
+	Fuzzy     bool    `json:"fuzzy,omitempty"`
+}
+
+// NewSearchQuery separa text en palabras, todo lo que no es letra o numero separa
+// palabras y se ignora
+func NewSearchQuery(text string, limit int) (SearchQuery, error) {
+	q := SearchQuery{Terms: words(text), Limit: limit}
+	var v domain.Validator
+	v.Check(len(q.Terms) > 0, "q", "must have at least one letter or number")
+	v.Check(len(q.Terms) <= MaxSearchTerms, "q", fmt.Sprintf("can't have more than %d words", MaxSearchTerms))
+	v.Check(limit >= 1 && limit <= MaxLimit, "limit", fmt.Sprintf("must be between 1 and %d", MaxLimit))
+	if err := v.Err("invalid query"); err != nil {
+		return SearchQuery{}, err
+	}
+	return q, nil
+}
+
+// Fuzzy indica si la busqueda es corta y se puede repetir tolerando errores de tipeo
+func (q SearchQuery) Fuzzy() bool {
+	if len(q.Terms) > FuzzyMaxTerms {
+		return false
+	}
+	for _, term := range q.Terms {
+		if len([]rune(term)) < fuzzyMinLength {
+			return false
+		}
+	}
+	return true
+}
+
+// words devuelve las palabras de text en minusculas
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// searchProducts resuelve la busqueda en memoria, para los repositorios que no tienen
+// un indice de texto. Una palabra completa suma mas que el comienzo de una palabra
+func searchProducts(products []domain.Product, q SearchQuery) []SearchHit {
+	var hits []SearchHit
+	for _, p := range products {
+		name := words(p.Name)
+		score := 0.0
+		for _, term := range q.Terms {
+			best := 0.0
+			for _, word := range name {
+				if word == term {
+					best = 1
+				} else if strings.HasPrefix(word, term) && best < 0.5 {
+					best = 0.5
+				}
+			}
+			if best == 0 {
+				score = 0
+				break
+			}
+			score += best
+		}
+		if score > 0 {
+			hits = append(hits, SearchHit{Product: p, Score: score})
+		}
+	}
+	return rankHits(hits, q.Limit)
+}
+
+// fuzzySearch busca los productos que tienen, para cada palabra de la busqueda, una
+// palabra a pocas letras de distancia o que empieza a pocas letras de distancia
+func fuzzySearch(products []domain.Product, q SearchQuery) []SearchHit {
+	var hits []SearchHit
+	for _, p := range products {
+		name := words(p.Name)
+		score := 0.0
+		for _, term := range q.Terms {
+			best := -1
+			for _, word := range name {
+				if d, ok := fuzzyMatch(term, word); ok && (best < 0 || d < best) {
+					best = d
+				}
+			}
+			if best < 0 {
+				score = 0
+				break
+			}
+			score += 1 / float64(1+best)
+		}
+		if score > 0 {
+			hits = append(hits, SearchHit{Product: p, Score: score, Fuzzy: true})
+		}
+	}
+	return rankHits(hits, q.Limit)
+}
+
+// fuzzyPieces parte term en maxEdits+1 pedazos seguidos. Cada error cambia a lo sumo
+// un pedazo, por eso una palabra a pocos errores de term tiene alguno de ellos intacto
+func fuzzyPieces(term string) []string {
+	runes := []rune(term)
+	n := maxEdits(len(runes)) + 1
+	pieces := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		pieces = append(pieces, string(runes[i*len(runes)/n:(i+1)*len(runes)/n]))
+	}
+	return pieces
+}
+
+// fuzzyPatterns devuelve los patrones de LIKE que filtran los candidatos de term, o
+// nil si algun pedazo es mas corto que fuzzyMinPiece y term no filtra. Las palabras
+// solo tienen letras y numeros, no hace falta escapar los comodines de LIKE
+func fuzzyPatterns(term string) []string {
+	var patterns []string
+	for _, piece := range fuzzyPieces(term) {
+		if len([]rune(piece)) < fuzzyMinPiece {
+			return nil
+		}
+		patterns = append(patterns, "%"+piece+"%")
+	}
+	return patterns
+}
+
+// fuzzySQL arma para dialect la consulta de una tanda de candidatos de una busqueda
+// con errores: los fuzzyBatch productos siguientes al id after cuyo nombre tiene,
+// para cada palabra de q que filtra, alguno de sus pedazos
+func fuzzySQL(q SearchQuery, dialect database.Dialect, after int) (statement, error) {
+	b := query.New(dialect, productTable)
+	for _, term := range q.Terms {
+		if patterns := fuzzyPatterns(term); patterns != nil {
+			b.WhereLike("name", patterns...)
+		}
+	}
+	if after > 0 {
+		b.Where("id", query.Gt, after)
+	}
+	sql, args, err := b.OrderBy("id", false).Limit(fuzzyBatch).Select()
+	return statement{sql, args}, err
+}
+
+// fuzzyScan resuelve una busqueda con errores en una base: recorre los candidatos en
+// tandas de fuzzyBatch, load ejecuta la consulta de cada una, y se queda con los
+// mejores q.Limit. Asi da los mismos resultados que fuzzySearch sobre toda la tabla
+func fuzzyScan(q SearchQuery, dialect database.Dialect, load func(statement) ([]domain.Product, error)) ([]SearchHit, error) {
+	var hits []SearchHit
+	after := 0
+	for {
+		stmt, err := fuzzySQL(q, dialect, after)
+		if err != nil {
+			return nil, err
+		}
+		batch, err := load(stmt)
+		if err != nil {
+			return nil, err
+		}
+		hits = rankHits(append(hits, fuzzySearch(batch, q)...), q.Limit)
+		if len(batch) < fuzzyBatch {
+			return hits, nil
+		}
+		after = batch[len(batch)-1].Id
+	}
+}
+
+// fuzzyMatch devuelve la distancia entre term y word, o entre term y el comienzo de
+// word, si esta dentro de los errores que tolera el largo de term
+func fuzzyMatch(term, word string) (int, bool) {
+	t, w := []rune(term), []rune(word)
+	d := levenshtein(t, w)
+	if len(w) > len(t) {
+		if prefix := levenshtein(t, w[:len(t)]); prefix < d {
+			d = prefix
+		}
+	}
+	return d, d <= maxEdits(len(t))
+}
+
+// maxEdits son los errores que se toleran en una palabra de n letras
+func maxEdits(n int) int {
+	if n < 6 {
+		return 1
+	}
+	return 2
+}
+
+// levenshtein devuelve la cantidad de letras que hay que agregar, borrar o cambiar
+// para pasar de a a b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// minInt devuelve el menor de values
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// rankHits ordena los resultados de mayor a menor Score, por id cuando empatan, y se
+// queda con los primeros limit
+func rankHits(hits []SearchHit, limit int) []SearchHit {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Id < hits[j].Id
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// highlight devuelve name escapado como html con las palabras encontradas entre
+// <mark> y </mark>. Se marca el comienzo de la palabra que coincide con la busqueda,
+// o la palabra completa si se encontro con errores de tipeo
+func highlight(name string, terms []string, fuzzy bool) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			b.WriteString(html.EscapeString(string(runes[i])))
+			i++
+			continue
+		}
+		end := i
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		word := runes[i:end]
+		marked := markedLength(word, terms, fuzzy)
+		if marked > 0 {
+			b.WriteString("<mark>" + html.EscapeString(string(word[:marked])) + "</mark>")
+		}
+		b.WriteString(html.EscapeString(string(word[marked:])))
+		i = end
+	}
+	return b.String()
+}
+
+// markedLength devuelve cuantas letras de word se marcan, cero si ninguna palabra de
+// la busqueda coincide con ella
+func markedLength(word []rune, terms []string, fuzzy bool) int {
+	lower := strings.ToLower(string(word))
+	marked := 0
+	for _, term := range terms {
+		if fuzzy {
+			if _, ok := fuzzyMatch(term, lower); ok {
+				return len(word)
+			}
+		}
+		if strings.HasPrefix(lower, term) && len([]rune(term)) > marked {
+			marked = len([]rune(term))
+		}
+	}
+	if marked > len(word) {
+		return len(word)
+	}
+	return marked
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// scanHits lee las filas de una busqueda, las columnas de productTable y el score
+func scanHits(rows *sql.Rows) ([]SearchHit, error) {
+	defer rows.Close()
+	var hits []SearchHit
+	for rows.Next() {
+		var hit SearchHit
+		if err := rows.Scan(&hit.Id, &hit.Name, &hit.Quantity, &hit.CodeValue, &hit.IsPublished, &hit.Expiration, &hit.Price, &hit.WarehouseId, &hit.Score); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// booleanQuery arma la busqueda de MATCH ... AGAINST en modo booleano de mysql, todas
+// las palabras obligatorias y como prefijo: +oil* +marg*
+func booleanQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = "+" + term + "*"
+	}
+	return strings.Join(parts, " ")
+}
+
+// fts5Query arma la busqueda de MATCH de fts5 en sqlite, cada palabra entre comillas
+// y como prefijo: "oil"* "marg"*
+func fts5Query(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = `"` + term + `"*`
+	}
+	return strings.Join(parts, " ")
+}
+
+// tsQuery arma el tsquery de postgres, todas las palabras como prefijo: oil:* & marg:*
+func tsQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
@@ -0,0 +1,199 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSearchQuery(t *testing.T) {
+	t.Run("Success, words in lower case", func(t *testing.T) {
+		// act
+		q, err := NewSearchQuery("  Oil - MARG* +pan'", 10)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, SearchQuery{Terms: []string{"oil", "marg", "pan"}, Limit: 10}, q)
+	})
+
+	t.Run("failed, no words", func(t *testing.T) {
+		// act
+		_, err := NewSearchQuery(` "*-+ `, 0)
+
+		// assert
+		var validation *domain.ValidationError
+		assert.True(t, errors.As(err, &validation))
+		assert.Equal(t, []domain.FieldError{
+			{Field: "q", Message: "must have at least one letter or number"},
+			{Field: "limit", Message: "must be between 1 and 100"},
+		}, validation.Fields)
+	})
+
+	t.Run("failed, too many words", func(t *testing.T) {
+		// act
+		_, err := NewSearchQuery("a b c d e f g h i", 10)
+
+		// assert
+		assert.EqualError(t, err, "invalid query: q can't have more than 8 words")
+	})
+}
+
+func TestSearchQuery_Fuzzy(t *testing.T) {
+	assert.True(t, SearchQuery{Terms: []string{"zuchini"}}.Fuzzy())
+	assert.True(t, SearchQuery{Terms: []string{"oil", "margarin"}}.Fuzzy())
+	assert.False(t, SearchQuery{Terms: []string{"oil", "margarin", "pack"}}.Fuzzy())
+	assert.False(t, SearchQuery{Terms: []string{"ol"}}.Fuzzy())
+}
+
+func TestFuzzySearch(t *testing.T) {
+	products := []domain.Product{
+		{Id: 1, Name: "Zucchini Bread"},
+		{Id: 2, Name: "Oil - Margarine"},
+		{Id: 3, Name: "Zucchini"},
+		{Id: 4, Name: "Bread - Rye"},
+	}
+
+	cases := []struct {
+		name  string
+		terms []string
+		exp   []int
+	}{
+		{"missing letter", []string{"zuchini"}, []int{1, 3}},
+		{"swapped letters count as two", []string{"mrag"}, nil},
+		{"changed letter in a prefix", []string{"margerine"}, []int{2}},
+		{"every word", []string{"zuchini", "bred"}, []int{1}},
+		{"too far", []string{"zoo"}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			hits := fuzzySearch(products, SearchQuery{Terms: c.terms, Limit: 10})
+
+			// assert
+			var ids []int
+			for _, hit := range hits {
+				assert.True(t, hit.Fuzzy)
+				ids = append(ids, hit.Id)
+			}
+			assert.Equal(t, c.exp, ids)
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	cases := []struct {
+		name  string
+		text  string
+		terms []string
+		fuzzy bool
+		exp   string
+	}{
+		{"prefix", "Oil - Margarine", []string{"marg"}, false, "Oil - <mark>Marg</mark>arine"},
+		{"every word", "Oil - Margarine", []string{"oil", "margarine"}, false, "<mark>Oil</mark> - <mark>Margarine</mark>"},
+		{"only word starts", "Panettone", []string{"ton"}, false, "Panettone"},
+		{"escaped", "Fish & <Chips>", []string{"chip"}, false, "Fish &amp; &lt;<mark>Chip</mark>s&gt;"},
+		{"fuzzy marks the word", "Zucchini Bread", []string{"zuchini"}, true, "<mark>Zucchini</mark> Bread"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			got := highlight(c.text, c.terms, c.fuzzy)
+
+			// assert
+			assert.Equal(t, c.exp, got)
+		})
+	}
+}
+
+func TestSearchSQL(t *testing.T) {
+	terms := []string{"oil", "marg"}
+
+	assert.Equal(t, "+oil* +marg*", booleanQuery(terms))
+	assert.Equal(t, `"oil"* "marg"*`, fts5Query(terms))
+	assert.Equal(t, "oil:* & marg:*", tsQuery(terms))
+}
+
+func TestFuzzyPieces(t *testing.T) {
+	assert.Equal(t, []string{"o", "il"}, fuzzyPieces("oil"))
+	assert.Equal(t, []string{"zu", "ch", "ini"}, fuzzyPieces("zuchini"))
+	assert.Equal(t, []string{"pa", "ñal"}, fuzzyPieces("pañal"))
+}
+
+func TestFuzzyPatterns(t *testing.T) {
+	assert.Nil(t, fuzzyPatterns("oil"))
+	assert.Nil(t, fuzzyPatterns("zuchini"))
+	assert.Equal(t, []string{"%mar%", "%gar%", "%ine%"}, fuzzyPatterns("margarine"))
+}
+
+func TestFuzzySQL(t *testing.T) {
+	t.Run("Success, only long pieces filter", func(t *testing.T) {
+		// act
+		stmt, err := fuzzySQL(SearchQuery{Terms: []string{"oil", "margarine"}, Limit: 10}, database.Postgres, 0)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT "id", "name", "quantity", "code_value", "is_published", "expiration", "price", "id_warehouse" FROM "products" `+
+			`WHERE (LOWER("name") LIKE $1 OR LOWER("name") LIKE $2 OR LOWER("name") LIKE $3) ORDER BY "id" LIMIT $4`, stmt.sql)
+		assert.Equal(t, []interface{}{"%mar%", "%gar%", "%ine%", fuzzyBatch}, stmt.args)
+	})
+
+	t.Run("Success, next batch after an id", func(t *testing.T) {
+		// act
+		stmt, err := fuzzySQL(SearchQuery{Terms: []string{"oil"}, Limit: 10}, database.MySQL, 1000)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT `id`, `name`, `quantity`, `code_value`, `is_published`, `expiration`, `price`, `id_warehouse` FROM `products` "+
+			"WHERE `id` > ? ORDER BY `id` LIMIT ?", stmt.sql)
+		assert.Equal(t, []interface{}{1000, fuzzyBatch}, stmt.args)
+	})
+}
+
+func TestService_Search(t *testing.T) {
+	newService := func() Service {
+		db := database.NewMemoryDB()
+		warehouse := db.InsertWarehouse(domain.Warehouse{Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100})
+		for _, name := range []string{"Zucchini Bread", "Oil - Margarine", "Bread - Rye"} {
+			_, err := db.InsertProduct(domain.Product{Name: name, Quantity: 1, CodeValue: name, Expiration: domain.NewDate(2030, 1, 1), Price: 1, WarehouseId: warehouse.Id})
+			assert.NoError(t, err)
+		}
+		return NewService(NewMemoryRepository(db))
+	}
+
+	t.Run("Success, prefix with highlight", func(t *testing.T) {
+		// act
+		hits, err := newService().Search(context.Background(), SearchQuery{Terms: []string{"bread"}, Limit: 10})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, hits, 2)
+		assert.Equal(t, "Zucchini <mark>Bread</mark>", hits[0].Highlight)
+		assert.Equal(t, "<mark>Bread</mark> - Rye", hits[1].Highlight)
+		assert.False(t, hits[0].Fuzzy)
+	})
+
+	t.Run("Success, fuzzy when nothing matches", func(t *testing.T) {
+		// act
+		hits, err := newService().Search(context.Background(), SearchQuery{Terms: []string{"zuchini"}, Limit: 10})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, hits, 1)
+		assert.Equal(t, "Zucchini Bread", hits[0].Name)
+		assert.Equal(t, "<mark>Zucchini</mark> Bread", hits[0].Highlight)
+		assert.True(t, hits[0].Fuzzy)
+	})
+
+	t.Run("Success, long queries are not fuzzy", func(t *testing.T) {
+		// act
+		hits, err := newService().Search(context.Background(), SearchQuery{Terms: []string{"zuchini", "bred", "ry"}, Limit: 10})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []SearchHit{}, hits)
+	})
+}
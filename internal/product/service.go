@@ -1,30 +1,42 @@
 package product
 
 import (
+	"context"
 	"errors"
+	"strings"
 
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
 	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
 )
 
 var (
 	ErrNotFound     = errors.New("product not found")
-	ErrInternal     = errors.New("internal error")
+	ErrInternal     = dberr.ErrInternal
 	ErrAlreadyExist = errors.New("already exists a product with that product code")
 )
 
 type Service interface {
 	// GetByID busca un producto por su id
-	GetByID(id int) (domain.Product, error)
+	GetByID(ctx context.Context, id int) (domain.Product, error)
+	// GetByCode busca un producto por su code_value
+	GetByCode(ctx context.Context, code string) (domain.Product, error)
+	// GetByCodes busca los productos de varios code_value, ver NewCodes
+	GetByCodes(ctx context.Context, codes []string) (CodeLookup, error)
 	// GetAll busca todos los productos
-	GetAll() ([]domain.Product, error)
+	GetAll(ctx context.Context) ([]domain.Product, error)
+	// List busca una pagina de productos, sin Limit usa DefaultLimit
+	List(ctx context.Context, q ListQuery) (Page, error)
+	// Search busca productos por nombre, si no encuentra ninguno y la busqueda es
+	// corta la repite tolerando errores de tipeo
+	Search(ctx context.Context, q SearchQuery) ([]SearchHit, error)
 	// Create agrega un nuevo producto
-	Create(p domain.Product) (domain.Product, error)
+	Create(ctx context.Context, p domain.Product) (domain.Product, error)
 	// Delete elimina un producto
-	Delete(id int) error
+	Delete(ctx context.Context, id int) error
 	// Update actualiza un producto
-	Update(id int, p domain.Product) (domain.Product, error)
+	Update(ctx context.Context, id int, p domain.Product) (domain.Product, error)
 	// GetFullData busca un producto por su id, trae datos de warehouse
-	GetFullData(id int) (domain.ProductFull, error)
+	GetFullData(ctx context.Context, id int) (domain.ProductFull, error)
 }
 
 type service struct {
@@ -36,40 +48,87 @@ func NewService(r Repository) Service {
 	return &service{r}
 }
 
-func (s *service) GetByID(id int) (domain.Product, error) {
-	p, err := s.r.GetByID(id)
+func (s *service) GetByID(ctx context.Context, id int) (domain.Product, error) {
+	p, err := s.r.GetByID(ctx, id)
 	if err != nil {
 		return domain.Product{}, err
 	}
 	return p, nil
 }
 
-func (s *service) GetFullData(id int) (domain.ProductFull, error) {
-	productFull, err := s.r.GetFullData(id)
+func (s *service) GetByCode(ctx context.Context, code string) (domain.Product, error) {
+	return s.r.GetByCode(ctx, strings.TrimSpace(code))
+}
+
+func (s *service) GetByCodes(ctx context.Context, codes []string) (CodeLookup, error) {
+	codes, err := NewCodes(codes)
+	if err != nil {
+		return CodeLookup{}, err
+	}
+	products, err := s.r.GetByCodes(ctx, codes)
+	if err != nil {
+		return CodeLookup{}, err
+	}
+	return newCodeLookup(codes, products), nil
+}
+
+func (s *service) GetFullData(ctx context.Context, id int) (domain.ProductFull, error) {
+	productFull, err := s.r.GetFullData(ctx, id)
 	if err != nil {
 		return domain.ProductFull{}, err
 	}
 	return productFull, nil
 }
 
-func (s *service) GetAll() ([]domain.Product, error) {
-	products, err := s.r.GetAll()
+func (s *service) GetAll(ctx context.Context) ([]domain.Product, error) {
+	products, err := s.r.GetAll(ctx)
 	if err != nil {
 		return []domain.Product{}, err
 	}
 	return products, nil
 }
 
-func (s *service) Create(p domain.Product) (domain.Product, error) {
-	p, err := s.r.Create(p)
+func (s *service) List(ctx context.Context, q ListQuery) (Page, error) {
+	if q.Limit == 0 {
+		q.Limit = DefaultLimit
+	}
+	if err := q.Validate(); err != nil {
+		return Page{}, err
+	}
+	return s.r.List(ctx, q)
+}
+
+func (s *service) Search(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	hits, err := s.r.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	// los indices de texto no toleran errores, la busqueda con errores es mas lenta y
+	// por eso solo se hace cuando no hubo resultados
+	if len(hits) == 0 && q.Fuzzy() {
+		if hits, err = s.r.FuzzySearch(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+	for i := range hits {
+		hits[i].Highlight = highlight(hits[i].Name, q.Terms, hits[i].Fuzzy)
+	}
+	if hits == nil {
+		hits = []SearchHit{}
+	}
+	return hits, nil
+}
+
+func (s *service) Create(ctx context.Context, p domain.Product) (domain.Product, error) {
+	p, err := s.r.Create(ctx, p)
 	if err != nil {
 		return domain.Product{}, err
 	}
 	return p, nil
 }
 
-func (s *service) Update(id int, u domain.Product) (domain.Product, error) {
-	p, err := s.r.GetByID(id)
+func (s *service) Update(ctx context.Context, id int, u domain.Product) (domain.Product, error) {
+	p, err := s.r.GetByID(ctx, id)
 	if err != nil {
 		return domain.Product{}, err
 	}
@@ -79,7 +138,7 @@ func (s *service) Update(id int, u domain.Product) (domain.Product, error) {
 	if u.CodeValue != "" {
 		p.CodeValue = u.CodeValue
 	}
-	if u.Expiration != "" {
+	if !u.Expiration.IsZero() {
 		p.Expiration = u.Expiration
 	}
 	if u.Quantity > 0 {
@@ -88,15 +147,15 @@ func (s *service) Update(id int, u domain.Product) (domain.Product, error) {
 	if u.Price > 0 {
 		p.Price = u.Price
 	}
-	p, err = s.r.Update(id, p)
+	p, err = s.r.Update(ctx, id, p)
 	if err != nil {
 		return domain.Product{}, err
 	}
 	return p, nil
 }
 
-func (s *service) Delete(id int) error {
-	err := s.r.Delete(id)
+func (s *service) Delete(ctx context.Context, id int) error {
+	err := s.r.Delete(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,202 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+// sqliteRepository struct definition
+type sqliteRepository struct {
+	database database.Conn
+}
+
+// NewSQLiteRepository constructor function
+func NewSQLiteRepository(database database.Conn) Repository {
+	return &sqliteRepository{database}
+}
+
+// Create method to insert a new product into the products table
+func (repository *sqliteRepository) Create(ctx context.Context, product domain.Product) (domain.Product, error) {
+	if product.Expiration.IsZero() {
+		return domain.Product{}, ErrParsingDate
+	}
+
+	result, err := repository.database.ExecContext(ctx, `INSERT INTO products(name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES( ?, ?, ?, ?, ?, ?, ?)`,
+		product.Name, product.Quantity, product.CodeValue, product.IsPublished, product.Expiration, product.Price, product.WarehouseId)
+	if err != nil {
+		return domain.Product{}, dberr.SQLite(err)
+	}
+	insertedId, err := result.LastInsertId()
+	if err != nil {
+		return domain.Product{}, dberr.SQLite(err)
+	}
+	product.Id = int(insertedId)
+	return product, nil
+}
+
+func (repository *sqliteRepository) GetAll(ctx context.Context) ([]domain.Product, error) {
+	query := `SELECT id, name, quantity, code_value, is_published, expiration, price, id_warehouse FROM products`
+	rows, err := repository.database.QueryContext(ctx, query)
+	if err != nil {
+		return nil, dberr.SQLite(err)
+	}
+	defer rows.Close()
+
+	var products []domain.Product
+	for rows.Next() {
+		var product domain.Product
+		if err := rows.Scan(&product.Id, &product.Name, &product.Quantity, &product.CodeValue, &product.IsPublished, &product.Expiration, &product.Price, &product.WarehouseId); err != nil {
+			return nil, dberr.SQLite(err)
+		}
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dberr.SQLite(err)
+	}
+
+	return products, nil
+}
+
+func (repository *sqliteRepository) List(ctx context.Context, q ListQuery) (Page, error) {
+	count, list, err := listSQL(q, database.SQLite)
+	if err != nil {
+		return Page{}, err
+	}
+	var total int
+	if err := repository.database.QueryRowContext(ctx, count.sql, count.args...).Scan(&total); err != nil {
+		return Page{}, dberr.SQLite(err)
+	}
+	rows, err := repository.database.QueryContext(ctx, list.sql, list.args...)
+	if err != nil {
+		return Page{}, dberr.SQLite(err)
+	}
+	products, err := scanProducts(rows)
+	if err != nil {
+		return Page{}, dberr.SQLite(err)
+	}
+	return newPage(products, total, q), nil
+}
+
+func (repository *sqliteRepository) Search(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	query := `SELECT p.id, p.name, p.quantity, p.code_value, p.is_published, p.expiration, p.price, p.id_warehouse, -bm25(products_fts) AS score
+	FROM products_fts JOIN products p ON p.id = products_fts.rowid WHERE products_fts MATCH ? ORDER BY score DESC, p.id LIMIT ?`
+	rows, err := repository.database.QueryContext(ctx, query, fts5Query(q.Terms), q.Limit)
+	if err != nil {
+		return nil, dberr.SQLite(err)
+	}
+	hits, err := scanHits(rows)
+	if err != nil {
+		return nil, dberr.SQLite(err)
+	}
+	return hits, nil
+}
+
+func (repository *sqliteRepository) FuzzySearch(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	return fuzzyScan(q, database.SQLite, func(stmt statement) ([]domain.Product, error) {
+		rows, err := repository.database.QueryContext(ctx, stmt.sql, stmt.args...)
+		if err != nil {
+			return nil, dberr.SQLite(err)
+		}
+		products, err := scanProducts(rows)
+		if err != nil {
+			return nil, dberr.SQLite(err)
+		}
+		return products, nil
+	})
+}
+
+func (repository *sqliteRepository) GetFullData(ctx context.Context, id int) (domain.ProductFull, error) {
+	query := `SELECT p.id, p.name, p.quantity, p.code_value, p.is_published, p.expiration, p.price, p.id_warehouse, w.name, w.address FROM products p
+	INNER JOIN warehouses w ON p.id_warehouse = w.id WHERE p.id = ?`
+	row := repository.database.QueryRowContext(ctx, query, id)
+	var productFull = domain.ProductFull{}
+	err := row.Scan(&productFull.Id, &productFull.Name, &productFull.Quantity, &productFull.CodeValue, &productFull.IsPublished, &productFull.Expiration, &productFull.Price, &productFull.WarehouseId, &productFull.WarehouseName, &productFull.WarehouseAddress)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ProductFull{}, ErrNotFound
+		}
+		return domain.ProductFull{}, dberr.SQLite(err)
+	}
+	return productFull, nil
+}
+
+func (repository *sqliteRepository) GetByID(ctx context.Context, id int) (product domain.Product, err error) {
+	query := `SELECT id, name, quantity, code_value, is_published, expiration, price, id_warehouse FROM products where id = ?`
+	row := repository.database.QueryRowContext(ctx, query, id)
+	err = row.Scan(&product.Id, &product.Name, &product.Quantity, &product.CodeValue, &product.IsPublished, &product.Expiration, &product.Price, &product.WarehouseId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Product{}, ErrNotFound
+		}
+		return domain.Product{}, dberr.SQLite(err)
+	}
+
+	return product, nil
+}
+
+func (repository *sqliteRepository) GetByCode(ctx context.Context, code string) (domain.Product, error) {
+	products, err := repository.GetByCodes(ctx, []string{code})
+	if err != nil {
+		return domain.Product{}, err
+	}
+	return productByCode(code, products)
+}
+
+func (repository *sqliteRepository) GetByCodes(ctx context.Context, codes []string) ([]domain.Product, error) {
+	stmt, err := codesSQL(codes, database.SQLite)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := repository.database.QueryContext(ctx, stmt.sql, stmt.args...)
+	if err != nil {
+		return nil, dberr.SQLite(err)
+	}
+	products, err := scanProducts(rows)
+	if err != nil {
+		return nil, dberr.SQLite(err)
+	}
+	return products, nil
+}
+
+func (repository *sqliteRepository) Update(ctx context.Context, id int, product domain.Product) (domain.Product, error) {
+	if product.Expiration.IsZero() {
+		return domain.Product{}, ErrParsingDate
+	}
+	result, err := repository.database.ExecContext(ctx, `UPDATE products SET name = ?, quantity = ?, code_value = ?, is_published = ?, expiration = ?, price = ?, id_warehouse = ? WHERE id = ?`,
+		product.Name, product.Quantity, product.CodeValue, product.IsPublished, product.Expiration, product.Price, product.WarehouseId, id)
+	if err != nil {
+		return domain.Product{}, dberr.SQLite(err)
+	}
+	// sqlite counts matched rows, so zero really means there is no such product
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return domain.Product{}, dberr.SQLite(err)
+	}
+	if rowsAffected == 0 {
+		return domain.Product{}, ErrNotFound
+	}
+	product.Id = id
+	return product, nil
+}
+
+func (repository *sqliteRepository) Delete(ctx context.Context, id int) error {
+	result, err := repository.database.ExecContext(ctx, `DELETE FROM products WHERE id = ?`, id)
+	if err != nil {
+		return dberr.SQLite(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dberr.SQLite(err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSQLiteDatabase abre una base sqlite temporal con los mismos datos que espera txdb
+func newSQLiteDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "my_db.sqlite"))
+	assert.NoError(t, err)
+	assert.NoError(t, database.Migrate(db, database.SQLite))
+	_, err = db.Exec(`INSERT INTO warehouses(id, name, address, telephone, capacity) VALUES
+		(1, 'Main Warehouse', '221 Baker Street', '4555666', 100),
+		(2, 'SuperMarket', '123 Main Street', '555-555-5555', 2222);
+	INSERT INTO products(name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES
+		('Oil - Margarine', 439, 'S82254D', 1, '2021-12-15', 71.42, 1),
+		('Pineapple - Canned, Rings', 345, 'M4637', 1, '2021-08-09', 352.79, 2)`)
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRepositorySQLite_GetAll(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		// act
+		pr, err := rp.GetAll(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, pr, 2)
+	})
+
+	t.Run("failed, context canceled", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// act
+		pr, err := rp.GetAll(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, dberr.ErrCanceled)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositorySQLite_GetByID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		exp := domain.Product{Id: 1, Name: "Oil - Margarine", Quantity: 439, CodeValue: "S82254D", IsPublished: true, Expiration: domain.NewDate(2021, 12, 15), Price: 71.42, WarehouseId: 1}
+
+		// act
+		pr, err := rp.GetByID(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		// act
+		pr, err := rp.GetByID(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositorySQLite_GetFullData(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		// act
+		pr, err := rp.GetFullData(context.Background(), 2)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "SuperMarket", pr.WarehouseName)
+		assert.Equal(t, "123 Main Street", pr.WarehouseAddress)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		// act
+		pr, err := rp.GetFullData(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositorySQLite_Create(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		product := domain.Product{Name: "Cookie - Oatmeal", Quantity: 130, CodeValue: "M7157", Expiration: domain.NewDate(2022, 1, 28), Price: 275.47, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+		exp := product
+		exp.Id = 3
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+	})
+
+	t.Run("failed, duplicate code value", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "S82254D", Expiration: domain.NewDate(2022, 1, 28), Price: 1, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrDuplicateEntry)
+		assert.Empty(t, pr)
+	})
+
+	t.Run("failed, warehouse does not exist", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "NEW1", Expiration: domain.NewDate(2022, 1, 28), Price: 1, WarehouseId: 100}
+
+		// act
+		pr, err := rp.Create(context.Background(), product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrForeignKey)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositorySQLite_Update(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		product := domain.Product{Name: "Oil - Olive", Quantity: 10, CodeValue: "S82254D", Expiration: domain.NewDate(2023, 12, 15), Price: 80, WarehouseId: 2}
+		exp := product
+		exp.Id = 1
+
+		// act
+		pr, err := rp.Update(context.Background(), 1, product)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pr)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		product := domain.Product{Name: "Oil", Quantity: 1, CodeValue: "NEW1", Expiration: domain.NewDate(2023, 12, 15), Price: 1, WarehouseId: 1}
+
+		// act
+		pr, err := rp.Update(context.Background(), 100, product)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, pr)
+	})
+}
+
+func TestRepositorySQLite_Delete(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		// act
+		err := rp.Delete(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		// act
+		err := rp.Delete(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestRepositorySQLite_FuzzySearch(t *testing.T) {
+	t.Run("Success, match after the first batch", func(t *testing.T) {
+		// arrange
+		db := newSQLiteDatabase(t)
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		for i := 0; i < fuzzyBatch+10; i++ {
+			_, err = tx.Exec(`INSERT INTO products(name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES(?, 1, ?, 1, '2030-01-01', 1, 1)`,
+				fmt.Sprintf("Tomato Soup %d", i), fmt.Sprintf("F%d", i))
+			assert.NoError(t, err)
+		}
+		_, err = tx.Exec(`INSERT INTO products(name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES('Olive Oyl', 1, 'LAST', 1, '2030-01-01', 1, 1)`)
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Commit())
+		rp := NewSQLiteRepository(db)
+
+		// act
+		hits, err := rp.FuzzySearch(context.Background(), SearchQuery{Terms: []string{"oil"}, Limit: 10})
+
+		// assert
+		assert.NoError(t, err)
+		codes := make([]string, len(hits))
+		for i, hit := range hits {
+			codes[i] = hit.CodeValue
+		}
+		assert.Contains(t, codes, "LAST")
+		assert.Contains(t, codes, "S82254D")
+	})
+}
@@ -0,0 +1,92 @@
+package warehouse_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-txdb"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/product"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/warehouse"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/warehouse/warehousetest"
+	"github.com/bootcamp-go/consignas-go-db.git/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	if dsn := os.Getenv("MYSQL_DSN"); dsn != "" {
+		txdb.Register("txdb_mysql", "mysql", dsn)
+	}
+}
+
+func TestConformanceJSON(t *testing.T) {
+	warehousetest.RunRepositoryTests(t, func(t *testing.T) (warehouse.Repository, product.Repository) {
+		dir := t.TempDir()
+		productsPath := filepath.Join(dir, "products.json")
+		warehousesPath := filepath.Join(dir, "warehouses.json")
+		assert.NoError(t, os.WriteFile(productsPath, []byte(`[]`), 0644))
+		assert.NoError(t, os.WriteFile(warehousesPath, []byte(`[]`), 0644))
+		storage := store.NewJsonStore(productsPath, warehousesPath)
+		return warehouse.NewRepository(storage), product.NewRepository(storage)
+	})
+}
+
+func TestConformanceJSONJournal(t *testing.T) {
+	warehousetest.RunRepositoryTests(t, func(t *testing.T) (warehouse.Repository, product.Repository) {
+		dir := t.TempDir()
+		productsPath := filepath.Join(dir, "products.json")
+		warehousesPath := filepath.Join(dir, "warehouses.json")
+		assert.NoError(t, os.WriteFile(productsPath, []byte(`[]`), 0644))
+		assert.NoError(t, os.WriteFile(warehousesPath, []byte(`[]`), 0644))
+		storage := store.NewLogStore(productsPath, warehousesPath, store.DefaultCompactEvery)
+		t.Cleanup(func() { storage.Close() })
+		return warehouse.NewRepository(storage), product.NewRepository(storage)
+	})
+}
+
+func TestConformanceMemory(t *testing.T) {
+	warehousetest.RunRepositoryTests(t, func(t *testing.T) (warehouse.Repository, product.Repository) {
+		db := database.NewMemoryDB()
+		return warehouse.NewMemoryRepository(db), product.NewMemoryRepository(db)
+	})
+}
+
+func TestConformanceSQLite(t *testing.T) {
+	warehousetest.RunRepositoryTests(t, func(t *testing.T) (warehouse.Repository, product.Repository) {
+		db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "my_db.sqlite"))
+		assert.NoError(t, err)
+		assert.NoError(t, database.Migrate(db, database.SQLite))
+		t.Cleanup(func() { db.Close() })
+		return warehouse.NewSQLiteRepository(db), product.NewSQLiteRepository(db)
+	})
+}
+
+// TestConformancePostgres corre cada test en una transaccion de txdb sobre POSTGRES_DSN,
+// el driver txdb_postgres se registra en postgres_repository_test.go
+func TestConformancePostgres(t *testing.T) {
+	if os.Getenv("POSTGRES_DSN") == "" {
+		t.Skip("POSTGRES_DSN not set")
+	}
+	warehousetest.RunRepositoryTests(t, func(t *testing.T) (warehouse.Repository, product.Repository) {
+		db, err := sql.Open("txdb_postgres", t.Name())
+		assert.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		assert.NoError(t, database.Migrate(db, database.Postgres))
+		return warehouse.NewPostgresRepository(db), product.NewPostgresRepository(db)
+	})
+}
+
+// TestConformanceMySQL corre cada test en una transaccion de txdb sobre MYSQL_DSN
+func TestConformanceMySQL(t *testing.T) {
+	if os.Getenv("MYSQL_DSN") == "" {
+		t.Skip("MYSQL_DSN not set")
+	}
+	warehousetest.RunRepositoryTests(t, func(t *testing.T) (warehouse.Repository, product.Repository) {
+		db, err := sql.Open("txdb_mysql", t.Name())
+		assert.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return warehouse.NewMySQLRepository(db), product.NewMySQLRepository(db)
+	})
+}
@@ -0,0 +1,124 @@
+package warehouse
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+// ErrHasProducts es el error de eliminar un warehouse que todavia tiene productos
+// sin indicar que hacer con ellos
+var ErrHasProducts = errors.New("warehouse has products")
+
+// DeletePolicy indica que pasa con los productos de un warehouse que se elimina
+type DeletePolicy string
+
+const (
+	// DeleteRefuse no elimina el warehouse si tiene productos
+	DeleteRefuse DeletePolicy = "refuse"
+	// DeleteCascade elimina el warehouse junto con sus productos
+	DeleteCascade DeletePolicy = "cascade"
+	// DeleteReassign mueve los productos a otro warehouse antes de eliminarlo
+	DeleteReassign DeletePolicy = "reassign"
+)
+
+// DeleteOptions es la eliminacion de un warehouse, ReassignTo es el warehouse que
+// recibe los productos y solo se usa con DeleteReassign
+type DeleteOptions struct {
+	Policy     DeletePolicy
+	ReassignTo int
+}
+
+// Validate revisa que la politica exista y que ReassignTo este solo cuando se
+// reasigna, y que no sea el warehouse id que se elimina
+func (o DeleteOptions) Validate(id int) error {
+	var v domain.Validator
+	switch o.Policy {
+	case DeleteRefuse, DeleteCascade:
+		v.Check(o.ReassignTo == 0, "reassign_to", "can only be used with products=reassign")
+	case DeleteReassign:
+		v.Check(o.ReassignTo > 0, "reassign_to", "must be the id of another warehouse")
+		v.Check(o.ReassignTo != id, "reassign_to", "can't be the deleted warehouse")
+	default:
+		v.Check(false, "products", "must be refuse, cascade or reassign")
+	}
+	return v.Err("invalid delete")
+}
+
+// DeleteResult cuenta los productos que se eliminaron o se movieron junto con el
+// warehouse
+type DeleteResult struct {
+	Policy   DeletePolicy `json:"products"`
+	Products int          `json:"affected_products"`
+}
+
+// deleteSQL elimina el warehouse id en una transaccion de conn segun opts, con los
+// placeholders de dialect y traduciendo los errores de la base con translate. Los
+// productos se revisan dentro de la transaccion, y si otro request agrega uno antes
+// del DELETE la clave foranea lo rechaza y no cambia nada
+func deleteSQL(ctx context.Context, conn database.Conn, dialect database.Dialect, translate func(error) error, id int, opts DeleteOptions) (DeleteResult, error) {
+	p := dialect.Placeholder
+	result := DeleteResult{Policy: opts.Policy}
+	err := database.InTx(ctx, conn, func(tx database.Querier) error {
+		count := func(query string, arg interface{}) (int, error) {
+			var n int
+			err := tx.QueryRowContext(ctx, query, arg).Scan(&n)
+			return n, err
+		}
+		exec := func(query string, args ...interface{}) (int, error) {
+			res, err := tx.ExecContext(ctx, query, args...)
+			if err != nil {
+				return 0, err
+			}
+			n, err := res.RowsAffected()
+			return int(n), err
+		}
+
+		exists, err := count(`SELECT COUNT(*) FROM warehouses WHERE id = `+p(1), id)
+		if err != nil {
+			return translate(err)
+		}
+		if exists == 0 {
+			return ErrNotFound
+		}
+		switch opts.Policy {
+		case DeleteCascade:
+			result.Products, err = exec(`DELETE FROM products WHERE id_warehouse = `+p(1), id)
+		case DeleteReassign:
+			if exists, err = count(`SELECT COUNT(*) FROM warehouses WHERE id = `+p(1), opts.ReassignTo); err == nil && exists == 0 {
+				return ErrForeignKey
+			}
+			if err == nil {
+				result.Products, err = exec(`UPDATE products SET id_warehouse = `+p(1)+` WHERE id_warehouse = `+p(2), opts.ReassignTo, id)
+			}
+		default:
+			var products int
+			if products, err = count(`SELECT COUNT(*) FROM products WHERE id_warehouse = `+p(1), id); err == nil && products > 0 {
+				return ErrHasProducts
+			}
+		}
+		if err != nil {
+			return translate(err)
+		}
+
+		if _, err := exec(`DELETE FROM warehouses WHERE id = `+p(1), id); err != nil {
+			if err = translate(err); errors.Is(err, ErrForeignKey) {
+				return ErrHasProducts
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		var dbErr *dberr.Error
+		if errors.As(err, &dbErr) || errors.Is(err, ErrNotFound) || errors.Is(err, ErrHasProducts) || errors.Is(err, ErrForeignKey) {
+			return DeleteResult{}, err
+		}
+		// los errores de BeginTx y Commit vienen directo del driver
+		return DeleteResult{}, translate(err)
+	}
+	return result, nil
+}
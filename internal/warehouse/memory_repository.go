@@ -0,0 +1,87 @@
+package warehouse
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+// memoryRepository struct definition
+type memoryRepository struct {
+	database *database.MemoryDB
+}
+
+// NewMemoryRepository constructor function
+func NewMemoryRepository(database *database.MemoryDB) Repository {
+	return &memoryRepository{database}
+}
+
+// memoryError maps a MemoryDB error onto the repository errors
+func memoryError(err error) error {
+	switch {
+	case errors.Is(err, database.ErrRowNotFound):
+		return ErrNotFound
+	case errors.Is(err, database.ErrUniqueViolation):
+		return ErrDuplicateEntry
+	case errors.Is(err, database.ErrForeignKeyViolation):
+		return ErrForeignKey
+	default:
+		return ErrInternal
+	}
+}
+
+// Create method to insert a new warehouse
+func (repository *memoryRepository) Create(ctx context.Context, warehouse domain.Warehouse) (domain.Warehouse, error) {
+	return repository.database.InsertWarehouse(warehouse), nil
+}
+
+func (repository *memoryRepository) GetByID(ctx context.Context, id int) (domain.Warehouse, error) {
+	warehouse, err := repository.database.Warehouse(id)
+	if err != nil {
+		return domain.Warehouse{}, memoryError(err)
+	}
+	return warehouse, nil
+}
+
+func (repository *memoryRepository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
+	return repository.database.Warehouses(), nil
+}
+
+func (repository *memoryRepository) Update(ctx context.Context, id int, warehouse domain.Warehouse) (domain.Warehouse, error) {
+	warehouse.Id = id
+	if err := repository.database.UpdateWarehouse(warehouse); err != nil {
+		return domain.Warehouse{}, memoryError(err)
+	}
+	return warehouse, nil
+}
+
+func (repository *memoryRepository) Delete(ctx context.Context, id int, opts DeleteOptions) (DeleteResult, error) {
+	moveTo := 0
+	if opts.Policy == DeleteReassign {
+		moveTo = opts.ReassignTo
+	}
+	n, err := repository.database.DeleteWarehouse(id, opts.Policy == DeleteCascade, moveTo)
+	if err != nil {
+		// al reasignar la clave foranea falla porque no existe el destino, si no porque
+		// quedan productos
+		if errors.Is(err, database.ErrForeignKeyViolation) && opts.Policy != DeleteReassign {
+			return DeleteResult{}, ErrHasProducts
+		}
+		return DeleteResult{}, memoryError(err)
+	}
+	return DeleteResult{Policy: opts.Policy, Products: n}, nil
+}
+
+func (repository *memoryRepository) ReportProducts(ctx context.Context, id int) (domain.ReportProducts, error) {
+	warehouse, err := repository.database.Warehouse(id)
+	if err != nil {
+		return domain.ReportProducts{}, memoryError(err)
+	}
+	return domain.ReportProducts{
+		WarehouseName: warehouse.Name,
+		ProductCount:  strconv.Itoa(repository.database.CountProducts(id)),
+	}, nil
+}
@@ -0,0 +1,112 @@
+package warehouse
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMemoryDatabase crea una base en memoria con los mismos datos que espera txdb
+func newMemoryDatabase() *database.MemoryDB {
+	db := database.NewMemoryDB()
+	db.InsertWarehouse(domain.Warehouse{Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100})
+	db.InsertWarehouse(domain.Warehouse{Name: "SuperMarket", Address: "123 Main Street", Telephone: "555-555-5555", Capacity: 2222})
+	db.InsertProduct(domain.Product{Name: "Oil - Margarine", Quantity: 439, CodeValue: "S82254D", IsPublished: true, Expiration: domain.NewDate(2021, 12, 15), Price: 71.42, WarehouseId: 1})
+	db.InsertProduct(domain.Product{Name: "Pineapple - Canned, Rings", Quantity: 345, CodeValue: "M4637", IsPublished: true, Expiration: domain.NewDate(2021, 8, 9), Price: 352.79, WarehouseId: 1})
+	return db
+}
+
+func TestRepositoryMemory_GetAll(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		exp := []domain.Warehouse{
+			{Id: 1, Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100},
+			{Id: 2, Name: "SuperMarket", Address: "123 Main Street", Telephone: "555-555-5555", Capacity: 2222},
+		}
+
+		// act
+		wr, err := rp.GetAll(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, wr)
+	})
+}
+
+func TestRepositoryMemory_GetByID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		exp := domain.Warehouse{Id: 1, Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100}
+
+		// act
+		wr, err := rp.GetByID(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, wr)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		// act
+		wr, err := rp.GetByID(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, wr)
+	})
+}
+
+func TestRepositoryMemory_Create(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		warehouse := domain.Warehouse{Name: "New Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100}
+
+		// act
+		wr, err := rp.Create(context.Background(), warehouse)
+		exp := warehouse
+		exp.Id = 3
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, wr)
+	})
+}
+
+func TestRepositoryMemory_ReportProducts(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		exp := domain.ReportProducts{WarehouseName: "Main Warehouse", ProductCount: "2"}
+
+		// act
+		rep, err := rp.ReportProducts(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, rep)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewMemoryRepository(newMemoryDatabase())
+
+		// act
+		_, err := rp.ReportProducts(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
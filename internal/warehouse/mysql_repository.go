@@ -1,77 +1,57 @@
 package warehouse
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
 	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
-	"github.com/go-sql-driver/mysql"
 )
 
-// Define custom error variables
+// Errors of the repositories, the database ones are translated by dberr
 var (
-	ErrAccessDenied       = errors.New("access denied for user")
-	ErrNoDatabaseSelected = errors.New("no database selected")
-	ErrNotNullColumn      = errors.New("column cannot be null")
-	ErrUnknownColumn      = errors.New("unknown column")
-	ErrDuplicateEntry     = errors.New("duplicate entry")
-	ErrSyntaxError        = errors.New("syntax error")
-	ErrTableDoesNotExist  = errors.New("table does not exist")
+	ErrAccessDenied       = dberr.ErrAccessDenied
+	ErrNoDatabaseSelected = dberr.ErrNoDatabaseSelected
+	ErrNotNullColumn      = dberr.ErrNotNullColumn
+	ErrUnknownColumn      = dberr.ErrUnknownColumn
+	ErrDuplicateEntry     = dberr.ErrDuplicateEntry
+	ErrSyntaxError        = dberr.ErrSyntaxError
+	ErrTableDoesNotExist  = dberr.ErrTableDoesNotExist
+	ErrForeignKey         = dberr.ErrForeignKey
+	ErrDeadlock           = dberr.ErrDeadlock
 	ErrParsingDate        = errors.New("error parsing date")
 )
 
 type Repository interface {
-	GetByID(id int) (domain.Warehouse, error)
-	Create(p domain.Warehouse) (domain.Warehouse, error)
-	GetAll() ([]domain.Warehouse, error)
-	ReportProducts(id int) (domain.ReportProducts, error)
+	GetByID(ctx context.Context, id int) (domain.Warehouse, error)
+	Create(ctx context.Context, p domain.Warehouse) (domain.Warehouse, error)
+	GetAll(ctx context.Context) ([]domain.Warehouse, error)
+	ReportProducts(ctx context.Context, id int) (domain.ReportProducts, error)
+	// Update reemplaza los datos del warehouse id
+	Update(ctx context.Context, id int, w domain.Warehouse) (domain.Warehouse, error)
+	// Delete elimina el warehouse id y hace con sus productos lo que indica opts
+	Delete(ctx context.Context, id int, opts DeleteOptions) (DeleteResult, error)
 }
 
 // mySQLRepository struct definition
 type mySQLRepository struct {
-	database *sql.DB
+	database database.Conn
 }
 
 // NewMySQLRepository constructor function
-func NewMySQLRepository(database *sql.DB) Repository {
+func NewMySQLRepository(database database.Conn) Repository {
 	return &mySQLRepository{database}
 }
 
 // Create method to insert a new product into the products table
-func (repository *mySQLRepository) Create(warehouse domain.Warehouse) (domain.Warehouse, error) {
+func (repository *mySQLRepository) Create(ctx context.Context, warehouse domain.Warehouse) (domain.Warehouse, error) {
 
-	statement, err := repository.database.Prepare(`INSERT INTO warehouses(name, address, telephone, capacity) VALUES( ?, ?, ?, ?)`)
+	result, err := repository.database.ExecContext(ctx, `INSERT INTO warehouses(name, address, telephone, capacity) VALUES( ?, ?, ?, ?)`,
+		warehouse.Name, warehouse.Address, warehouse.Telephone, warehouse.Capacity)
 	if err != nil {
-		return domain.Warehouse{}, err
-	}
-	defer statement.Close()
-	var result sql.Result
-	result, err = statement.Exec(warehouse.Name, warehouse.Address, warehouse.Telephone, warehouse.Capacity)
-	if err != nil {
-		fmt.Println(err)
-		mysqlError, ok := err.(*mysql.MySQLError)
-		if !ok {
-			return domain.Warehouse{}, ErrInternal
-		}
-		switch mysqlError.Number {
-		case 1044, 1045:
-			return domain.Warehouse{}, ErrAccessDenied
-		case 1046:
-			return domain.Warehouse{}, ErrNoDatabaseSelected
-		case 1048:
-			return domain.Warehouse{}, ErrNotNullColumn
-		case 1054:
-			return domain.Warehouse{}, ErrUnknownColumn
-		case 1062:
-			return domain.Warehouse{}, ErrDuplicateEntry
-		case 1064:
-			return domain.Warehouse{}, ErrSyntaxError
-		case 1146:
-			return domain.Warehouse{}, ErrTableDoesNotExist
-		default:
-			return domain.Warehouse{}, ErrInternal
-		}
+		return domain.Warehouse{}, dberr.MySQL(err)
 	}
 	insertedId, err := result.LastInsertId()
 	if err != nil {
@@ -81,60 +61,26 @@ func (repository *mySQLRepository) Create(warehouse domain.Warehouse) (domain.Wa
 	return warehouse, nil
 }
 
-func (repository *mySQLRepository) GetByID(id int) (warehouse domain.Warehouse, err error) {
+func (repository *mySQLRepository) GetByID(ctx context.Context, id int) (warehouse domain.Warehouse, err error) {
 	query := `SELECT id, name, address, telephone, capacity FROM warehouses where id = ?`
-	row := repository.database.QueryRow(query, id)
+	row := repository.database.QueryRowContext(ctx, query, id)
 	err = row.Scan(&warehouse.Id, &warehouse.Name, &warehouse.Address, &warehouse.Telephone, &warehouse.Capacity)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Warehouse{}, ErrNotFound
 		}
 
-		mysqlError, ok := err.(*mysql.MySQLError)
-		if !ok {
-			return domain.Warehouse{}, ErrInternal
-		}
-		switch mysqlError.Number {
-		case 1044, 1045:
-			return domain.Warehouse{}, ErrAccessDenied
-		case 1046:
-			return domain.Warehouse{}, ErrNoDatabaseSelected
-		case 1054:
-			return domain.Warehouse{}, ErrUnknownColumn
-		case 1064:
-			return domain.Warehouse{}, ErrSyntaxError
-		case 1146:
-			return domain.Warehouse{}, ErrTableDoesNotExist
-		default:
-			return domain.Warehouse{}, ErrInternal
-		}
+		return domain.Warehouse{}, dberr.MySQL(err)
 	}
 
 	return warehouse, nil
 }
 
-func (repository *mySQLRepository) GetAll() ([]domain.Warehouse, error) {
+func (repository *mySQLRepository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
 	query := (`SELECT id, name, address, telephone, capacity FROM warehouses`)
-	rows, err := repository.database.Query(query)
+	rows, err := repository.database.QueryContext(ctx, query)
 	if err != nil {
-		mysqlError, ok := err.(*mysql.MySQLError)
-		if !ok {
-			return nil, ErrInternal
-		}
-		switch mysqlError.Number {
-		case 1044, 1045:
-			return nil, ErrAccessDenied
-		case 1046:
-			return nil, ErrNoDatabaseSelected
-		case 1054:
-			return nil, ErrUnknownColumn
-		case 1064:
-			return nil, ErrSyntaxError
-		case 1146:
-			return nil, ErrTableDoesNotExist
-		default:
-			return nil, ErrInternal
-		}
+		return nil, dberr.MySQL(err)
 	}
 	defer rows.Close()
 
@@ -142,31 +88,55 @@ func (repository *mySQLRepository) GetAll() ([]domain.Warehouse, error) {
 	for rows.Next() {
 		var warehouse domain.Warehouse
 		if err := rows.Scan(&warehouse.Id, &warehouse.Name, &warehouse.Address, &warehouse.Telephone, &warehouse.Capacity); err != nil {
-			return nil, ErrInternal
+			return nil, dberr.MySQL(err)
 		}
 		warehouses = append(warehouses, warehouse)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, ErrInternal
+		return nil, dberr.MySQL(err)
 	}
 
 	return warehouses, nil
 }
 
-func (repository *mySQLRepository) ReportProducts(id int) (reportProducts domain.ReportProducts, err error) {
-	query := `SELECT w.name, count(*) FROM warehouses w 
+func (repository *mySQLRepository) ReportProducts(ctx context.Context, id int) (reportProducts domain.ReportProducts, err error) {
+	query := `SELECT w.name, count(p.id) FROM warehouses w 
 	LEFT JOIN products p
 	ON w.id = p.id_warehouse
 	WHERE w.id = ?
 	GROUP BY w.id`
-	row := repository.database.QueryRow(query, id)
+	row := repository.database.QueryRowContext(ctx, query, id)
 	err = row.Scan(&reportProducts.WarehouseName, &reportProducts.ProductCount)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return reportProducts, ErrNotFound
 		}
-		return reportProducts, ErrInternal
+		return reportProducts, dberr.MySQL(err)
 	}
 	return reportProducts, nil
 }
+
+func (repository *mySQLRepository) Update(ctx context.Context, id int, warehouse domain.Warehouse) (domain.Warehouse, error) {
+	result, err := repository.database.ExecContext(ctx, `UPDATE warehouses SET name = ?, address = ?, telephone = ?, capacity = ? WHERE id = ?`,
+		warehouse.Name, warehouse.Address, warehouse.Telephone, warehouse.Capacity, id)
+	if err != nil {
+		return domain.Warehouse{}, dberr.MySQL(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return domain.Warehouse{}, dberr.MySQL(err)
+	}
+	// mysql only counts changed rows, so zero can also mean the values were the same
+	if rowsAffected == 0 {
+		if _, err := repository.GetByID(ctx, id); err != nil {
+			return domain.Warehouse{}, err
+		}
+	}
+	warehouse.Id = id
+	return warehouse, nil
+}
+
+func (repository *mySQLRepository) Delete(ctx context.Context, id int, opts DeleteOptions) (DeleteResult, error) {
+	return deleteSQL(ctx, repository.database, database.MySQL, dberr.MySQL, id, opts)
+}
@@ -1,6 +1,7 @@
 package warehouse
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"testing"
@@ -30,7 +31,7 @@ func TestRepositoryMySQL_GetAll(t *testing.T) {
 		}
 
 		// act
-		wr, err := rp.GetAll()
+		wr, err := rp.GetAll(context.Background())
 
 		t.Log(wr[0])
 
@@ -52,7 +53,7 @@ func TestRepositoryMySQL_GetByID(t *testing.T) {
 		exp := domain.Warehouse{Id: 1, Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100}
 
 		// act
-		wr, err := rp.GetByID(1)
+		wr, err := rp.GetByID(context.Background(), 1)
 
 		// assert
 		assert.NoError(t, err)
@@ -68,7 +69,7 @@ func TestRepositoryMySQL_GetByID(t *testing.T) {
 		st := NewMySQLRepository(db)
 
 		// act
-		pr, err := st.GetByID(100)
+		pr, err := st.GetByID(context.Background(), 100)
 
 		// assert
 		assert.ErrorIs(t, err, ErrNotFound)
@@ -88,7 +89,7 @@ func TestRepositoryMySQL_Create(t *testing.T) {
 		warehouse := domain.Warehouse{Name: "New Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100}
 
 		// act
-		wr, err := rp.Create(warehouse)
+		wr, err := rp.Create(context.Background(), warehouse)
 		exp := warehouse
 		exp.Id = wr.Id
 
@@ -0,0 +1,107 @@
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+// postgresRepository struct definition
+type postgresRepository struct {
+	database database.Conn
+}
+
+// NewPostgresRepository constructor function
+func NewPostgresRepository(database database.Conn) Repository {
+	return &postgresRepository{database}
+}
+
+// Create method to insert a new warehouse into the warehouses table
+func (repository *postgresRepository) Create(ctx context.Context, warehouse domain.Warehouse) (domain.Warehouse, error) {
+	// lib/pq does not support LastInsertId, the id comes back with RETURNING
+	row := repository.database.QueryRowContext(ctx, `INSERT INTO warehouses(name, address, telephone, capacity) VALUES($1, $2, $3, $4) RETURNING id`,
+		warehouse.Name, warehouse.Address, warehouse.Telephone, warehouse.Capacity)
+	if err := row.Scan(&warehouse.Id); err != nil {
+		return domain.Warehouse{}, dberr.Postgres(err)
+	}
+	return warehouse, nil
+}
+
+func (repository *postgresRepository) GetByID(ctx context.Context, id int) (warehouse domain.Warehouse, err error) {
+	query := `SELECT id, name, address, telephone, capacity FROM warehouses where id = $1`
+	row := repository.database.QueryRowContext(ctx, query, id)
+	err = row.Scan(&warehouse.Id, &warehouse.Name, &warehouse.Address, &warehouse.Telephone, &warehouse.Capacity)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Warehouse{}, ErrNotFound
+		}
+		return domain.Warehouse{}, dberr.Postgres(err)
+	}
+
+	return warehouse, nil
+}
+
+func (repository *postgresRepository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
+	query := `SELECT id, name, address, telephone, capacity FROM warehouses`
+	rows, err := repository.database.QueryContext(ctx, query)
+	if err != nil {
+		return nil, dberr.Postgres(err)
+	}
+	defer rows.Close()
+
+	var warehouses []domain.Warehouse
+	for rows.Next() {
+		var warehouse domain.Warehouse
+		if err := rows.Scan(&warehouse.Id, &warehouse.Name, &warehouse.Address, &warehouse.Telephone, &warehouse.Capacity); err != nil {
+			return nil, dberr.Postgres(err)
+		}
+		warehouses = append(warehouses, warehouse)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dberr.Postgres(err)
+	}
+
+	return warehouses, nil
+}
+
+func (repository *postgresRepository) ReportProducts(ctx context.Context, id int) (reportProducts domain.ReportProducts, err error) {
+	query := `SELECT w.name, count(p.id) FROM warehouses w
+	LEFT JOIN products p
+	ON w.id = p.id_warehouse
+	WHERE w.id = $1
+	GROUP BY w.id`
+	row := repository.database.QueryRowContext(ctx, query, id)
+	err = row.Scan(&reportProducts.WarehouseName, &reportProducts.ProductCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return reportProducts, ErrNotFound
+		}
+		return reportProducts, dberr.Postgres(err)
+	}
+	return reportProducts, nil
+}
+
+func (repository *postgresRepository) Update(ctx context.Context, id int, warehouse domain.Warehouse) (domain.Warehouse, error) {
+	result, err := repository.database.ExecContext(ctx, `UPDATE warehouses SET name = $1, address = $2, telephone = $3, capacity = $4 WHERE id = $5`,
+		warehouse.Name, warehouse.Address, warehouse.Telephone, warehouse.Capacity, id)
+	if err != nil {
+		return domain.Warehouse{}, dberr.Postgres(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return domain.Warehouse{}, dberr.Postgres(err)
+	}
+	if rowsAffected == 0 {
+		return domain.Warehouse{}, ErrNotFound
+	}
+	warehouse.Id = id
+	return warehouse, nil
+}
+
+func (repository *postgresRepository) Delete(ctx context.Context, id int, opts DeleteOptions) (DeleteResult, error) {
+	return deleteSQL(ctx, repository.database, database.Postgres, dberr.Postgres, id, opts)
+}
@@ -0,0 +1,133 @@
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/DATA-DOG/go-txdb"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	if dsn := os.Getenv("POSTGRES_DSN"); dsn != "" {
+		txdb.Register("txdb_postgres", "postgres", dsn)
+	}
+}
+
+// newPostgresDatabase abre una transaccion de txdb sobre POSTGRES_DSN con los mismos
+// datos que espera txdb en mysql, se omite el test si no hay postgres configurado
+func newPostgresDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+	if os.Getenv("POSTGRES_DSN") == "" {
+		t.Skip("POSTGRES_DSN not set")
+	}
+	db, err := sql.Open("txdb_postgres", t.Name())
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	assert.NoError(t, database.Migrate(db, database.Postgres))
+	_, err = db.Exec(`TRUNCATE products, warehouses RESTART IDENTITY CASCADE;
+	INSERT INTO warehouses(name, address, telephone, capacity) VALUES
+		('Main Warehouse', '221 Baker Street', '4555666', 100),
+		('SuperMarket', '123 Main Street', '555-555-5555', 2222);
+	INSERT INTO products(name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES
+		('Oil - Margarine', 439, 'S82254D', TRUE, '2021-12-15', 71.42, 1),
+		('Pineapple - Canned, Rings', 345, 'M4637', TRUE, '2021-08-09', 352.79, 1)`)
+	assert.NoError(t, err)
+	return db
+}
+
+func TestRepositoryPostgres_GetAll(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		exp := []domain.Warehouse{
+			{Id: 1, Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100},
+			{Id: 2, Name: "SuperMarket", Address: "123 Main Street", Telephone: "555-555-5555", Capacity: 2222},
+		}
+
+		// act
+		wr, err := rp.GetAll(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, wr)
+	})
+}
+
+func TestRepositoryPostgres_GetByID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		exp := domain.Warehouse{Id: 1, Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100}
+
+		// act
+		wr, err := rp.GetByID(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, wr)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		// act
+		wr, err := rp.GetByID(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, wr)
+	})
+}
+
+func TestRepositoryPostgres_Create(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		warehouse := domain.Warehouse{Name: "New Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100}
+
+		// act
+		wr, err := rp.Create(context.Background(), warehouse)
+		exp := warehouse
+		exp.Id = 3
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, wr)
+	})
+}
+
+func TestRepositoryPostgres_ReportProducts(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		exp := domain.ReportProducts{WarehouseName: "Main Warehouse", ProductCount: "2"}
+
+		// act
+		rep, err := rp.ReportProducts(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, rep)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewPostgresRepository(newPostgresDatabase(t))
+
+		// act
+		_, err := rp.ReportProducts(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
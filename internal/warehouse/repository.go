@@ -0,0 +1,96 @@
+package warehouse
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/bootcamp-go/consignas-go-db.git/pkg/store"
+)
+
+type repository struct {
+	storage store.StoreInterface
+}
+
+// NewRepository crea un repositorio de warehouses sobre el store json, el mismo que
+// usa el repositorio de productos
+func NewRepository(storage store.StoreInterface) Repository {
+	return &repository{storage}
+}
+
+// storageError traduce los errores del store a los errores del repositorio
+func storageError(err error) error {
+	switch {
+	case errors.Is(err, store.ErrWarehouseNotFound):
+		return ErrNotFound
+	case errors.Is(err, store.ErrWarehouseInUse):
+		return ErrHasProducts
+	case errors.Is(err, store.ErrMoveToNotFound):
+		return ErrForeignKey
+	default:
+		return ErrInternal
+	}
+}
+
+func (r *repository) GetByID(ctx context.Context, id int) (domain.Warehouse, error) {
+	warehouse, err := r.storage.ReadWarehouse(id)
+	if err != nil {
+		return domain.Warehouse{}, storageError(err)
+	}
+	return warehouse, nil
+}
+
+func (r *repository) Create(ctx context.Context, warehouse domain.Warehouse) (domain.Warehouse, error) {
+	created, err := r.storage.CreateWarehouse(warehouse)
+	if err != nil {
+		return domain.Warehouse{}, storageError(err)
+	}
+	return created, nil
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
+	warehouses, err := r.storage.ReadWarehouses()
+	if err != nil {
+		return nil, ErrInternal
+	}
+	return warehouses, nil
+}
+
+func (r *repository) ReportProducts(ctx context.Context, id int) (domain.ReportProducts, error) {
+	warehouse, err := r.storage.ReadWarehouse(id)
+	if err != nil {
+		return domain.ReportProducts{}, storageError(err)
+	}
+	products, err := r.storage.ReadAll()
+	if err != nil {
+		return domain.ReportProducts{}, ErrInternal
+	}
+	count := 0
+	for _, p := range products {
+		if p.WarehouseId == id {
+			count++
+		}
+	}
+	return domain.ReportProducts{WarehouseName: warehouse.Name, ProductCount: strconv.Itoa(count)}, nil
+}
+
+func (r *repository) Update(ctx context.Context, id int, warehouse domain.Warehouse) (domain.Warehouse, error) {
+	warehouse.Id = id
+	if err := r.storage.UpdateWarehouse(warehouse); err != nil {
+		return domain.Warehouse{}, storageError(err)
+	}
+	return warehouse, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id int, opts DeleteOptions) (DeleteResult, error) {
+	moveTo := 0
+	if opts.Policy == DeleteReassign {
+		moveTo = opts.ReassignTo
+	}
+	n, err := r.storage.DeleteWarehouse(id, opts.Policy == DeleteCascade, moveTo)
+	if err != nil {
+		return DeleteResult{}, storageError(err)
+	}
+	return DeleteResult{Policy: opts.Policy, Products: n}, nil
+}
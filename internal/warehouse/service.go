@@ -1,21 +1,27 @@
 package warehouse
 
 import (
+	"context"
 	"errors"
 
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
 	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
 )
 
 var (
 	ErrNotFound = errors.New("warehouse not found")
-	ErrInternal = errors.New("internal error")
+	ErrInternal = dberr.ErrInternal
 )
 
 type Service interface {
-	GetByID(id int) (domain.Warehouse, error)
-	Create(p domain.Warehouse) (domain.Warehouse, error)
-	GetAll() ([]domain.Warehouse, error)
-	ReportProducts(id int) (reportProducts domain.ReportProducts, err error)
+	GetByID(ctx context.Context, id int) (domain.Warehouse, error)
+	Create(ctx context.Context, p domain.Warehouse) (domain.Warehouse, error)
+	GetAll(ctx context.Context) ([]domain.Warehouse, error)
+	ReportProducts(ctx context.Context, id int) (reportProducts domain.ReportProducts, err error)
+	// Update actualiza los campos de w que no tienen su valor cero
+	Update(ctx context.Context, id int, w domain.Warehouse) (domain.Warehouse, error)
+	// Delete elimina un warehouse, sin Policy se niega si tiene productos
+	Delete(ctx context.Context, id int, opts DeleteOptions) (DeleteResult, error)
 }
 
 type service struct {
@@ -27,34 +33,64 @@ func NewService(r Repository) Service {
 	return &service{r}
 }
 
-func (s *service) GetByID(id int) (domain.Warehouse, error) {
-	warehouse, err := s.r.GetByID(id)
+func (s *service) GetByID(ctx context.Context, id int) (domain.Warehouse, error) {
+	warehouse, err := s.r.GetByID(ctx, id)
 	if err != nil {
 		return domain.Warehouse{}, err
 	}
 	return warehouse, nil
 }
 
-func (s *service) Create(p domain.Warehouse) (domain.Warehouse, error) {
-	warehouse, err := s.r.Create(p)
+func (s *service) Create(ctx context.Context, p domain.Warehouse) (domain.Warehouse, error) {
+	warehouse, err := s.r.Create(ctx, p)
 	if err != nil {
 		return domain.Warehouse{}, err
 	}
 	return warehouse, nil
 }
 
-func (s *service) GetAll() ([]domain.Warehouse, error) {
-	warehouses, err := s.r.GetAll()
+func (s *service) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
+	warehouses, err := s.r.GetAll(ctx)
 	if err != nil {
 		return []domain.Warehouse{}, err
 	}
 	return warehouses, nil
 }
 
-func (s *service) ReportProducts(id int) (reportProducts domain.ReportProducts, err error) {
-	reportProducts, err = s.r.ReportProducts(id)
+func (s *service) ReportProducts(ctx context.Context, id int) (reportProducts domain.ReportProducts, err error) {
+	reportProducts, err = s.r.ReportProducts(ctx, id)
 	if err != nil {
 		return reportProducts, err
 	}
 	return reportProducts, nil
 }
+
+func (s *service) Update(ctx context.Context, id int, u domain.Warehouse) (domain.Warehouse, error) {
+	w, err := s.r.GetByID(ctx, id)
+	if err != nil {
+		return domain.Warehouse{}, err
+	}
+	if u.Name != "" {
+		w.Name = u.Name
+	}
+	if u.Address != "" {
+		w.Address = u.Address
+	}
+	if u.Telephone != "" {
+		w.Telephone = u.Telephone
+	}
+	if u.Capacity > 0 {
+		w.Capacity = u.Capacity
+	}
+	return s.r.Update(ctx, id, w)
+}
+
+func (s *service) Delete(ctx context.Context, id int, opts DeleteOptions) (DeleteResult, error) {
+	if opts.Policy == "" {
+		opts.Policy = DeleteRefuse
+	}
+	if err := opts.Validate(id); err != nil {
+		return DeleteResult{}, err
+	}
+	return s.r.Delete(ctx, id, opts)
+}
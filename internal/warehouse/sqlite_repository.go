@@ -0,0 +1,111 @@
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database/dberr"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+// sqliteRepository struct definition
+type sqliteRepository struct {
+	database database.Conn
+}
+
+// NewSQLiteRepository constructor function
+func NewSQLiteRepository(database database.Conn) Repository {
+	return &sqliteRepository{database}
+}
+
+// Create method to insert a new warehouse into the warehouses table
+func (repository *sqliteRepository) Create(ctx context.Context, warehouse domain.Warehouse) (domain.Warehouse, error) {
+	result, err := repository.database.ExecContext(ctx, `INSERT INTO warehouses(name, address, telephone, capacity) VALUES( ?, ?, ?, ?)`,
+		warehouse.Name, warehouse.Address, warehouse.Telephone, warehouse.Capacity)
+	if err != nil {
+		return domain.Warehouse{}, dberr.SQLite(err)
+	}
+	insertedId, err := result.LastInsertId()
+	if err != nil {
+		return domain.Warehouse{}, dberr.SQLite(err)
+	}
+	warehouse.Id = int(insertedId)
+	return warehouse, nil
+}
+
+func (repository *sqliteRepository) GetByID(ctx context.Context, id int) (warehouse domain.Warehouse, err error) {
+	query := `SELECT id, name, address, telephone, capacity FROM warehouses where id = ?`
+	row := repository.database.QueryRowContext(ctx, query, id)
+	err = row.Scan(&warehouse.Id, &warehouse.Name, &warehouse.Address, &warehouse.Telephone, &warehouse.Capacity)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Warehouse{}, ErrNotFound
+		}
+		return domain.Warehouse{}, dberr.SQLite(err)
+	}
+
+	return warehouse, nil
+}
+
+func (repository *sqliteRepository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
+	query := `SELECT id, name, address, telephone, capacity FROM warehouses`
+	rows, err := repository.database.QueryContext(ctx, query)
+	if err != nil {
+		return nil, dberr.SQLite(err)
+	}
+	defer rows.Close()
+
+	var warehouses []domain.Warehouse
+	for rows.Next() {
+		var warehouse domain.Warehouse
+		if err := rows.Scan(&warehouse.Id, &warehouse.Name, &warehouse.Address, &warehouse.Telephone, &warehouse.Capacity); err != nil {
+			return nil, dberr.SQLite(err)
+		}
+		warehouses = append(warehouses, warehouse)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dberr.SQLite(err)
+	}
+
+	return warehouses, nil
+}
+
+func (repository *sqliteRepository) ReportProducts(ctx context.Context, id int) (reportProducts domain.ReportProducts, err error) {
+	query := `SELECT w.name, count(p.id) FROM warehouses w
+	LEFT JOIN products p
+	ON w.id = p.id_warehouse
+	WHERE w.id = ?
+	GROUP BY w.id`
+	row := repository.database.QueryRowContext(ctx, query, id)
+	err = row.Scan(&reportProducts.WarehouseName, &reportProducts.ProductCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return reportProducts, ErrNotFound
+		}
+		return reportProducts, dberr.SQLite(err)
+	}
+	return reportProducts, nil
+}
+
+func (repository *sqliteRepository) Update(ctx context.Context, id int, warehouse domain.Warehouse) (domain.Warehouse, error) {
+	result, err := repository.database.ExecContext(ctx, `UPDATE warehouses SET name = ?, address = ?, telephone = ?, capacity = ? WHERE id = ?`,
+		warehouse.Name, warehouse.Address, warehouse.Telephone, warehouse.Capacity, id)
+	if err != nil {
+		return domain.Warehouse{}, dberr.SQLite(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return domain.Warehouse{}, dberr.SQLite(err)
+	}
+	if rowsAffected == 0 {
+		return domain.Warehouse{}, ErrNotFound
+	}
+	warehouse.Id = id
+	return warehouse, nil
+}
+
+func (repository *sqliteRepository) Delete(ctx context.Context, id int, opts DeleteOptions) (DeleteResult, error) {
+	return deleteSQL(ctx, repository.database, database.SQLite, dberr.SQLite, id, opts)
+}
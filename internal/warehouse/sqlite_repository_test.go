@@ -0,0 +1,121 @@
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/database"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSQLiteDatabase abre una base sqlite temporal con los mismos datos que espera txdb
+func newSQLiteDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "my_db.sqlite"))
+	assert.NoError(t, err)
+	assert.NoError(t, database.Migrate(db, database.SQLite))
+	_, err = db.Exec(`INSERT INTO warehouses(id, name, address, telephone, capacity) VALUES
+		(1, 'Main Warehouse', '221 Baker Street', '4555666', 100),
+		(2, 'SuperMarket', '123 Main Street', '555-555-5555', 2222);
+	INSERT INTO products(name, quantity, code_value, is_published, expiration, price, id_warehouse) VALUES
+		('Oil - Margarine', 439, 'S82254D', 1, '2021-12-15', 71.42, 1),
+		('Pineapple - Canned, Rings', 345, 'M4637', 1, '2021-08-09', 352.79, 1)`)
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRepositorySQLite_GetAll(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		exp := []domain.Warehouse{
+			{Id: 1, Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100},
+			{Id: 2, Name: "SuperMarket", Address: "123 Main Street", Telephone: "555-555-5555", Capacity: 2222},
+		}
+
+		// act
+		wr, err := rp.GetAll(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, wr)
+	})
+}
+
+func TestRepositorySQLite_GetByID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		exp := domain.Warehouse{Id: 1, Name: "Main Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100}
+
+		// act
+		wr, err := rp.GetByID(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, wr)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		// act
+		wr, err := rp.GetByID(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, wr)
+	})
+}
+
+func TestRepositorySQLite_Create(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		warehouse := domain.Warehouse{Name: "New Warehouse", Address: "221 Baker Street", Telephone: "4555666", Capacity: 100}
+
+		// act
+		wr, err := rp.Create(context.Background(), warehouse)
+		exp := warehouse
+		exp.Id = 3
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, wr)
+	})
+}
+
+func TestRepositorySQLite_ReportProducts(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		exp := domain.ReportProducts{WarehouseName: "Main Warehouse", ProductCount: "2"}
+
+		// act
+		rep, err := rp.ReportProducts(context.Background(), 1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, exp, rep)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		rp := NewSQLiteRepository(newSQLiteDatabase(t))
+
+		// act
+		_, err := rp.ReportProducts(context.Background(), 100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,299 @@
+// Package warehousetest contiene la suite de conformidad que debe pasar toda
+// implementacion de warehouse.Repository
+package warehousetest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/product"
+	"github.com/bootcamp-go/consignas-go-db.git/internal/warehouse"
+	"github.com/stretchr/testify/assert"
+)
+
+// missingID es un id que ningun backend deberia tener
+const missingID = 1 << 30
+
+// Setup crea un repositorio de warehouses aislado para un test junto con un repositorio
+// de productos sobre la misma base, que la suite usa para armar los reportes. La base
+// puede tener otros datos, la suite solo asume los warehouses que crea
+type Setup func(t *testing.T) (warehouse.Repository, product.Repository)
+
+// newWarehouse devuelve un warehouse valido con el nombre indicado
+func newWarehouse(name string) domain.Warehouse {
+	return domain.Warehouse{Name: name, Address: "221 Baker Street", Telephone: "4555666", Capacity: 100}
+}
+
+// createProducts crea n productos del warehouse id con codigos CONFORMANCE-0..n-1
+func createProducts(t *testing.T, products product.Repository, id, n int) []domain.Product {
+	t.Helper()
+	created := make([]domain.Product, n)
+	for i := range created {
+		p, err := products.Create(context.Background(), domain.Product{Name: "Cookie - Oatmeal", Quantity: 130, CodeValue: "CONFORMANCE-" + strconv.Itoa(i), IsPublished: true, Expiration: domain.NewDate(2022, 1, 28), Price: 275.47, WarehouseId: id})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		created[i] = p
+	}
+	return created
+}
+
+// RunRepositoryTests ejecuta la suite de conformidad contra los repositorios de setup
+func RunRepositoryTests(t *testing.T, setup Setup) {
+	ctx := context.Background()
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+			warehouse := newWarehouse("Conformance Warehouse")
+
+			// act
+			wr, err := rp.Create(ctx, warehouse)
+			stored, errGet := rp.GetByID(ctx, wr.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assert.NoError(t, errGet)
+			assert.NotZero(t, wr.Id)
+			exp := warehouse
+			exp.Id = wr.Id
+			assert.Equal(t, exp, wr)
+			assert.Equal(t, exp, stored)
+		})
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		t.Run("failed, not found", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+
+			// act
+			wr, err := rp.GetByID(ctx, missingID)
+
+			// assert
+			assert.ErrorIs(t, err, warehouse.ErrNotFound)
+			assert.Empty(t, wr)
+		})
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+			first, err := rp.Create(ctx, newWarehouse("Conformance Warehouse 1"))
+			assert.NoError(t, err)
+			second, err := rp.Create(ctx, newWarehouse("Conformance Warehouse 2"))
+			assert.NoError(t, err)
+
+			// act
+			warehouses, err := rp.GetAll(ctx)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Contains(t, warehouses, first)
+			assert.Contains(t, warehouses, second)
+		})
+	})
+
+	t.Run("ReportProducts", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			// arrange
+			rp, products := setup(t)
+			wr, err := rp.Create(ctx, newWarehouse("Conformance Warehouse"))
+			assert.NoError(t, err)
+			createProducts(t, products, wr.Id, 3)
+
+			// act
+			report, err := rp.ReportProducts(ctx, wr.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, domain.ReportProducts{WarehouseName: wr.Name, ProductCount: "3"}, report)
+		})
+
+		t.Run("Success, without products", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+			wr, err := rp.Create(ctx, newWarehouse("Conformance Warehouse"))
+			assert.NoError(t, err)
+
+			// act
+			report, err := rp.ReportProducts(ctx, wr.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, domain.ReportProducts{WarehouseName: wr.Name, ProductCount: "0"}, report)
+		})
+
+		t.Run("failed, not found", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+
+			// act
+			_, err := rp.ReportProducts(ctx, missingID)
+
+			// assert
+			assert.ErrorIs(t, err, warehouse.ErrNotFound)
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+			wr, err := rp.Create(ctx, newWarehouse("Conformance Warehouse"))
+			assert.NoError(t, err)
+			update := domain.Warehouse{Name: "Conformance Renamed", Address: "742 Evergreen Terrace", Telephone: "5551234", Capacity: 250}
+
+			// act
+			updated, err := rp.Update(ctx, wr.Id, update)
+			stored, errGet := rp.GetByID(ctx, wr.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assert.NoError(t, errGet)
+			update.Id = wr.Id
+			assert.Equal(t, update, updated)
+			assert.Equal(t, update, stored)
+		})
+
+		t.Run("Success, same values", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+			wr, err := rp.Create(ctx, newWarehouse("Conformance Warehouse"))
+			assert.NoError(t, err)
+
+			// act
+			updated, err := rp.Update(ctx, wr.Id, wr)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, wr, updated)
+		})
+
+		t.Run("failed, not found", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+
+			// act
+			_, err := rp.Update(ctx, missingID, newWarehouse("Conformance Warehouse"))
+
+			// assert
+			assert.ErrorIs(t, err, warehouse.ErrNotFound)
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("Success, refuse without products", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+			wr, err := rp.Create(ctx, newWarehouse("Conformance Warehouse"))
+			assert.NoError(t, err)
+
+			// act
+			result, err := rp.Delete(ctx, wr.Id, warehouse.DeleteOptions{Policy: warehouse.DeleteRefuse})
+			_, errGet := rp.GetByID(ctx, wr.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, warehouse.DeleteResult{Policy: warehouse.DeleteRefuse}, result)
+			assert.ErrorIs(t, errGet, warehouse.ErrNotFound)
+		})
+
+		t.Run("failed, refuse with products", func(t *testing.T) {
+			// arrange
+			rp, products := setup(t)
+			wr, err := rp.Create(ctx, newWarehouse("Conformance Warehouse"))
+			assert.NoError(t, err)
+			created := createProducts(t, products, wr.Id, 2)
+
+			// act
+			_, err = rp.Delete(ctx, wr.Id, warehouse.DeleteOptions{Policy: warehouse.DeleteRefuse})
+			_, errGet := rp.GetByID(ctx, wr.Id)
+			_, errProduct := products.GetByID(ctx, created[0].Id)
+
+			// assert
+			assert.ErrorIs(t, err, warehouse.ErrHasProducts)
+			assert.NoError(t, errGet)
+			assert.NoError(t, errProduct)
+		})
+
+		t.Run("Success, cascade", func(t *testing.T) {
+			// arrange
+			rp, products := setup(t)
+			wr, err := rp.Create(ctx, newWarehouse("Conformance Warehouse"))
+			assert.NoError(t, err)
+			created := createProducts(t, products, wr.Id, 2)
+
+			// act
+			result, err := rp.Delete(ctx, wr.Id, warehouse.DeleteOptions{Policy: warehouse.DeleteCascade})
+			_, errGet := rp.GetByID(ctx, wr.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, warehouse.DeleteResult{Policy: warehouse.DeleteCascade, Products: 2}, result)
+			assert.ErrorIs(t, errGet, warehouse.ErrNotFound)
+			for _, p := range created {
+				_, err := products.GetByID(ctx, p.Id)
+				assert.ErrorIs(t, err, product.ErrNotFound)
+			}
+		})
+
+		t.Run("Success, reassign", func(t *testing.T) {
+			// arrange
+			rp, products := setup(t)
+			wr, err := rp.Create(ctx, newWarehouse("Conformance Warehouse"))
+			assert.NoError(t, err)
+			target, err := rp.Create(ctx, newWarehouse("Conformance Target"))
+			assert.NoError(t, err)
+			created := createProducts(t, products, wr.Id, 2)
+
+			// act
+			result, err := rp.Delete(ctx, wr.Id, warehouse.DeleteOptions{Policy: warehouse.DeleteReassign, ReassignTo: target.Id})
+			_, errGet := rp.GetByID(ctx, wr.Id)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, warehouse.DeleteResult{Policy: warehouse.DeleteReassign, Products: 2}, result)
+			assert.ErrorIs(t, errGet, warehouse.ErrNotFound)
+			for _, p := range created {
+				moved, err := products.GetByID(ctx, p.Id)
+				assert.NoError(t, err)
+				assert.Equal(t, target.Id, moved.WarehouseId)
+			}
+		})
+
+		t.Run("failed, reassign to a missing warehouse", func(t *testing.T) {
+			// arrange
+			rp, products := setup(t)
+			wr, err := rp.Create(ctx, newWarehouse("Conformance Warehouse"))
+			assert.NoError(t, err)
+			created := createProducts(t, products, wr.Id, 1)
+
+			// act
+			_, err = rp.Delete(ctx, wr.Id, warehouse.DeleteOptions{Policy: warehouse.DeleteReassign, ReassignTo: missingID})
+			_, errGet := rp.GetByID(ctx, wr.Id)
+			stored, errProduct := products.GetByID(ctx, created[0].Id)
+
+			// assert
+			assert.ErrorIs(t, err, warehouse.ErrForeignKey)
+			assert.NoError(t, errGet)
+			assert.NoError(t, errProduct)
+			assert.Equal(t, wr.Id, stored.WarehouseId)
+		})
+
+		t.Run("failed, not found", func(t *testing.T) {
+			// arrange
+			rp, _ := setup(t)
+
+			// act
+			_, err := rp.Delete(ctx, missingID, warehouse.DeleteOptions{Policy: warehouse.DeleteCascade})
+
+			// assert
+			assert.ErrorIs(t, err, warehouse.ErrNotFound)
+		})
+	})
+}
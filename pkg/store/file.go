@@ -0,0 +1,57 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// acquireFileLock bloquea path entre procesos y devuelve la funcion que libera el bloqueo.
+// Se bloquea un archivo ".lock" aparte porque el archivo de datos se reemplaza con
+// rename en cada escritura y un bloqueo sobre el no sobreviviria al reemplazo
+func acquireFileLock(path string, exclusive bool) (func(), error) {
+	file, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err = lockFile(file, exclusive); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return func() {
+		unlockFile(file)
+		file.Close()
+	}, nil
+}
+
+// writeFileAtomic escribe data en un archivo temporal del mismo directorio, lo
+// sincroniza a disco y lo renombra sobre path, asi un corte a mitad de escritura
+// deja el archivo anterior intacto en lugar de uno truncado
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
@@ -1,16 +1,54 @@
 package store
 
-import "github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+import (
+	"errors"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+var (
+	// ErrNotFound se devuelve cuando el producto buscado no existe
+	ErrNotFound = errors.New("product not found")
+	// ErrWarehouseNotFound se devuelve cuando el warehouse de un producto no existe
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+	// ErrDuplicateCode se devuelve cuando otro producto ya tiene el mismo code_value
+	ErrDuplicateCode = errors.New("code value already exists")
+	// ErrWarehouseInUse se devuelve cuando se elimina un warehouse que todavia tiene
+	// productos sin indicar que hacer con ellos
+	ErrWarehouseInUse = errors.New("warehouse has products")
+	// ErrMoveToNotFound se devuelve cuando no existe el warehouse que recibe los
+	// productos de uno que se elimina
+	ErrMoveToNotFound = errors.New("warehouse to move the products to not found")
+)
 
 type StoreInterface interface {
 	// Read devuelve un producto por su id
 	Read(id int) (domain.Product, error)
-	// Create agrega un nuevo producto
-	Create(product domain.Product) error
-	// Update actualiza un producto
+	// ReadAll devuelve todos los productos
+	ReadAll() ([]domain.Product, error)
+	// ReadFull devuelve un producto por su id junto con los datos de su warehouse
+	ReadFull(id int) (domain.ProductFull, error)
+	// Create agrega un nuevo producto y lo devuelve con el id asignado, falla si el
+	// code_value ya existe o si el warehouse no existe
+	Create(product domain.Product) (domain.Product, error)
+	// Update actualiza un producto, con las mismas validaciones que Create
 	Update(product domain.Product) error
 	// Delete elimina un producto
 	Delete(id int) error
 	// Exists verifica si un producto existe
 	Exists(codeValue string) bool
+	// ReadWarehouse devuelve un warehouse por su id
+	ReadWarehouse(id int) (domain.Warehouse, error)
+	// ReadWarehouses devuelve todos los warehouses
+	ReadWarehouses() ([]domain.Warehouse, error)
+	// CreateWarehouse agrega un nuevo warehouse y lo devuelve con el id asignado
+	CreateWarehouse(warehouse domain.Warehouse) (domain.Warehouse, error)
+	// UpdateWarehouse reemplaza el warehouse con el mismo id
+	UpdateWarehouse(warehouse domain.Warehouse) error
+	// DeleteWarehouse elimina un warehouse. Sus productos se eliminan con cascade o se
+	// mueven al warehouse moveTo si no es cero, si no falla mientras tenga productos.
+	// Devuelve cuantos productos se eliminaron o movieron
+	DeleteWarehouse(id int, cascade bool, moveTo int) (int, error)
+	// Close libera los recursos del store, despues de llamarlo no se puede usar
+	Close() error
 }
@@ -4,12 +4,42 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
 )
 
 type jsonStore struct {
-	pathToFile string
+	pathToFile       string
+	pathToWarehouses string
+	// mu serializa las operaciones dentro del proceso, el bloqueo de archivo
+	// las serializa con otros procesos que usen el mismo archivo
+	mu sync.RWMutex
+}
+
+// lock toma el bloqueo del proceso y del archivo, exclusivo para las operaciones
+// que escriben, y devuelve la funcion que los libera
+func (s *jsonStore) lock(exclusive bool) (func(), error) {
+	if exclusive {
+		s.mu.Lock()
+	} else {
+		s.mu.RLock()
+	}
+	unlockMutex := s.mu.RUnlock
+	if exclusive {
+		unlockMutex = s.mu.Unlock
+	}
+	unlockFile, err := acquireFileLock(s.pathToFile, exclusive)
+	if err != nil {
+		unlockMutex()
+		return nil, err
+	}
+	return func() {
+		unlockFile()
+		unlockMutex()
+	}, nil
 }
 
 // loadProducts carga los productos desde un archivo json
@@ -26,64 +56,231 @@ func (s *jsonStore) loadProducts() ([]domain.Product, error) {
 	return products, nil
 }
 
+// loadWarehouses carga los warehouses desde el archivo json que acompaña a los productos
+func loadWarehouses(path string) ([]domain.Warehouse, error) {
+	var warehouses []domain.Warehouse
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(file, &warehouses)
+	if err != nil {
+		return nil, err
+	}
+	return warehouses, nil
+}
+
+// joinWarehouse agrega al producto los datos de su warehouse leidos de warehousesPath
+func joinWarehouse(product domain.Product, warehousesPath string) (domain.ProductFull, error) {
+	warehouses, err := loadWarehouses(warehousesPath)
+	if err != nil {
+		return domain.ProductFull{}, err
+	}
+	for _, warehouse := range warehouses {
+		if warehouse.Id == product.WarehouseId {
+			return domain.ProductFull{
+				Product:          product,
+				WarehouseName:    warehouse.Name,
+				WarehouseAddress: warehouse.Address,
+			}, nil
+		}
+	}
+	return domain.ProductFull{}, ErrWarehouseNotFound
+}
+
+// checkWarehouse verifica que el warehouse exista en warehousesPath
+func checkWarehouse(id int, warehousesPath string) error {
+	warehouses, err := loadWarehouses(warehousesPath)
+	if err != nil {
+		return err
+	}
+	for _, warehouse := range warehouses {
+		if warehouse.Id == id {
+			return nil
+		}
+	}
+	return ErrWarehouseNotFound
+}
+
+// checkProduct verifica que ningun otro producto use el code_value y que el warehouse exista
+func (s *jsonStore) checkProduct(products []domain.Product, product domain.Product) error {
+	for _, p := range products {
+		if p.Id != product.Id && p.CodeValue == product.CodeValue {
+			return ErrDuplicateCode
+		}
+	}
+	return checkWarehouse(product.WarehouseId, s.pathToWarehouses)
+}
+
 // saveProducts guarda los productos en un archivo json
 func (s *jsonStore) saveProducts(products []domain.Product) error {
 	bytes, err := json.Marshal(products)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(s.pathToFile, bytes, 0644)
+	return writeFileAtomic(s.pathToFile, bytes, 0644)
+}
+
+// sequencePath devuelve el archivo que guarda el ultimo id asignado
+func (s *jsonStore) sequencePath() string {
+	return s.pathToFile + ".seq"
+}
+
+// nextID reserva el siguiente id de producto con reserveID
+func (s *jsonStore) nextID(products []domain.Product) (int, error) {
+	last := 0
+	for _, p := range products {
+		if p.Id > last {
+			last = p.Id
+		}
+	}
+	return reserveID(s.sequencePath(), last)
 }
 
-// NewJsonStore crea un nuevo store de products
-func NewJsonStore(path string) StoreInterface {
+// reserveID reserva el siguiente id de la secuencia path y lo persiste antes de
+// guardar el dato, asi un id nunca se reutiliza aunque se borren datos o el proceso
+// caiga entre ambas escrituras. Si no existe la secuencia se parte de last, el mayor
+// id guardado
+func reserveID(path string, last int) (int, error) {
+	file, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		seq, err := strconv.Atoi(strings.TrimSpace(string(file)))
+		if err != nil {
+			return 0, err
+		}
+		if seq > last {
+			last = seq
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return 0, err
+	}
+	next := last + 1
+	if err := writeFileAtomic(path, []byte(strconv.Itoa(next)), 0644); err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// NewJsonStore crea un nuevo store de products, path es el archivo de productos
+// y warehousesPath el archivo de warehouses, que tambien se guardan con este store
+func NewJsonStore(path string, warehousesPath string) StoreInterface {
 	_, err := os.Stat(path)
 	if err != nil {
 		panic(err)
 	}
+	_, err = os.Stat(warehousesPath)
+	if err != nil {
+		panic(err)
+	}
 	return &jsonStore{
-		pathToFile: path,
+		pathToFile:       path,
+		pathToWarehouses: warehousesPath,
+	}
+}
+
+// findProduct busca un producto por su id dentro de products
+func findProduct(products []domain.Product, id int) (domain.Product, error) {
+	for _, product := range products {
+		if product.Id == id {
+			return product, nil
+		}
 	}
+	return domain.Product{}, ErrNotFound
 }
 
 func (s *jsonStore) Read(id int) (domain.Product, error) {
+	unlock, err := s.lock(false)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	defer unlock()
 	products, err := s.loadProducts()
 	if err != nil {
 		return domain.Product{}, err
 	}
-	for _, product := range products {
-		if product.Id == id {
-			return product, nil
-		}
+	return findProduct(products, id)
+}
+
+func (s *jsonStore) ReadAll() ([]domain.Product, error) {
+	unlock, err := s.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return s.loadProducts()
+}
+
+func (s *jsonStore) ReadFull(id int) (domain.ProductFull, error) {
+	unlock, err := s.lock(false)
+	if err != nil {
+		return domain.ProductFull{}, err
 	}
-	return domain.Product{}, errors.New("product not found")
+	defer unlock()
+	products, err := s.loadProducts()
+	if err != nil {
+		return domain.ProductFull{}, err
+	}
+	product, err := findProduct(products, id)
+	if err != nil {
+		return domain.ProductFull{}, err
+	}
+	return joinWarehouse(product, s.pathToWarehouses)
 }
 
-func (s *jsonStore) Create(product domain.Product) error {
+func (s *jsonStore) Create(product domain.Product) (domain.Product, error) {
+	unlock, err := s.lock(true)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	defer unlock()
 	products, err := s.loadProducts()
 	if err != nil {
-		return err
+		return domain.Product{}, err
+	}
+	product.Id = 0
+	if err = s.checkProduct(products, product); err != nil {
+		return domain.Product{}, err
+	}
+	product.Id, err = s.nextID(products)
+	if err != nil {
+		return domain.Product{}, err
 	}
-	product.Id = len(products) + 1
 	products = append(products, product)
-	return s.saveProducts(products)
+	if err = s.saveProducts(products); err != nil {
+		return domain.Product{}, err
+	}
+	return product, nil
 }
 
 func (s *jsonStore) Update(product domain.Product) error {
+	unlock, err := s.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	products, err := s.loadProducts()
 	if err != nil {
 		return err
 	}
 	for i, p := range products {
 		if p.Id == product.Id {
+			if err = s.checkProduct(products, product); err != nil {
+				return err
+			}
 			products[i] = product
 			return s.saveProducts(products)
 		}
 	}
-	return errors.New("product not found")
+	return ErrNotFound
 }
 
 func (s *jsonStore) Delete(id int) error {
+	unlock, err := s.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	products, err := s.loadProducts()
 	if err != nil {
 		return err
@@ -94,10 +291,15 @@ func (s *jsonStore) Delete(id int) error {
 			return s.saveProducts(products)
 		}
 	}
-	return errors.New("product not found")
+	return ErrNotFound
 }
 
 func (s *jsonStore) Exists(codeValue string) bool {
+	unlock, err := s.lock(false)
+	if err != nil {
+		return false
+	}
+	defer unlock()
 	products, err := s.loadProducts()
 	if err != nil {
 		return false
@@ -109,3 +311,94 @@ func (s *jsonStore) Exists(codeValue string) bool {
 	}
 	return false
 }
+
+// Close no hace nada, jsonStore toma el bloqueo de los archivos en cada operacion
+func (s *jsonStore) Close() error {
+	return nil
+}
+
+func (s *jsonStore) ReadWarehouse(id int) (domain.Warehouse, error) {
+	unlock, err := s.lock(false)
+	if err != nil {
+		return domain.Warehouse{}, err
+	}
+	defer unlock()
+	warehouses, err := loadWarehouses(s.pathToWarehouses)
+	if err != nil {
+		return domain.Warehouse{}, err
+	}
+	i, err := findWarehouse(warehouses, id)
+	if err != nil {
+		return domain.Warehouse{}, err
+	}
+	return warehouses[i], nil
+}
+
+func (s *jsonStore) ReadWarehouses() ([]domain.Warehouse, error) {
+	unlock, err := s.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return loadWarehouses(s.pathToWarehouses)
+}
+
+func (s *jsonStore) CreateWarehouse(warehouse domain.Warehouse) (domain.Warehouse, error) {
+	unlock, err := s.lock(true)
+	if err != nil {
+		return domain.Warehouse{}, err
+	}
+	defer unlock()
+	return createWarehouse(s.pathToWarehouses, warehouse)
+}
+
+func (s *jsonStore) UpdateWarehouse(warehouse domain.Warehouse) error {
+	unlock, err := s.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return updateWarehouse(s.pathToWarehouses, warehouse)
+}
+
+// DeleteWarehouse guarda primero los productos y despues los warehouses, si la segunda
+// escritura falla el warehouse sigue existiendo pero ningun producto queda apuntando
+// a un warehouse que no existe
+func (s *jsonStore) DeleteWarehouse(id int, cascade bool, moveTo int) (int, error) {
+	unlock, err := s.lock(true)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	warehouses, err := loadWarehouses(s.pathToWarehouses)
+	if err != nil {
+		return 0, err
+	}
+	products, err := s.loadProducts()
+	if err != nil {
+		return 0, err
+	}
+	warehouses, affected, err := removeWarehouse(warehouses, products, id, cascade, moveTo)
+	if err != nil {
+		return 0, err
+	}
+	if len(affected) > 0 {
+		kept := make([]domain.Product, 0, len(products))
+		for _, p := range products {
+			switch {
+			case p.WarehouseId != id:
+				kept = append(kept, p)
+			case !cascade:
+				p.WarehouseId = moveTo
+				kept = append(kept, p)
+			}
+		}
+		if err = s.saveProducts(kept); err != nil {
+			return 0, err
+		}
+	}
+	if err = saveWarehouses(s.pathToWarehouses, warehouses); err != nil {
+		return 0, err
+	}
+	return len(affected), nil
+}
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestJsonStore crea un store sobre archivos temporales vacios
+func newTestJsonStore(t *testing.T) (StoreInterface, string) {
+	t.Helper()
+	dir := t.TempDir()
+	productsPath := filepath.Join(dir, "products.json")
+	warehousesPath := filepath.Join(dir, "warehouses.json")
+	assert.NoError(t, os.WriteFile(productsPath, []byte("[]"), 0644))
+	assert.NoError(t, os.WriteFile(warehousesPath, []byte(`[{"id":1}]`), 0644))
+	return NewJsonStore(productsPath, warehousesPath), dir
+}
+
+func TestJsonStore_ConcurrentCreate(t *testing.T) {
+	t.Run("Success, no write is lost", func(t *testing.T) {
+		// arrange
+		st, _ := newTestJsonStore(t)
+		const writers = 50
+
+		// act
+		var wg sync.WaitGroup
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := st.Create(domain.Product{Name: "product", CodeValue: fmt.Sprintf("C%d", i), WarehouseId: 1})
+				assert.NoError(t, err)
+			}(i)
+		}
+		wg.Wait()
+		products, err := st.ReadAll()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, products, writers)
+	})
+}
+
+func TestJsonStore_AtomicWrite(t *testing.T) {
+	t.Run("Success, no temporary files are left", func(t *testing.T) {
+		// arrange
+		st, dir := newTestJsonStore(t)
+
+		// act
+		_, err := st.Create(domain.Product{Name: "product", CodeValue: "C1", WarehouseId: 1})
+		entries, _ := filepath.Glob(filepath.Join(dir, "products.json.tmp-*"))
+		info, _ := os.Stat(filepath.Join(dir, "products.json"))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+		assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	})
+}
+
+func TestJsonStore_Create(t *testing.T) {
+	t.Run("Success, returns the assigned id", func(t *testing.T) {
+		// arrange
+		st, _ := newTestJsonStore(t)
+
+		// act
+		first, errFirst := st.Create(domain.Product{Name: "product", CodeValue: "C1", WarehouseId: 1})
+		second, errSecond := st.Create(domain.Product{Name: "product", CodeValue: "C2", WarehouseId: 1})
+
+		// assert
+		assert.NoError(t, errFirst)
+		assert.NoError(t, errSecond)
+		assert.Equal(t, 1, first.Id)
+		assert.Equal(t, 2, second.Id)
+	})
+
+	t.Run("Success, ids are not reused after delete", func(t *testing.T) {
+		// arrange
+		st, _ := newTestJsonStore(t)
+		_, _ = st.Create(domain.Product{Name: "product", CodeValue: "C1", WarehouseId: 1})
+		last, _ := st.Create(domain.Product{Name: "product", CodeValue: "C2", WarehouseId: 1})
+		assert.NoError(t, st.Delete(last.Id))
+
+		// act
+		pr, err := st.Create(domain.Product{Name: "product", CodeValue: "C3", WarehouseId: 1})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 3, pr.Id)
+	})
+
+	t.Run("Success, sequence starts after existing ids", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		productsPath := filepath.Join(dir, "products.json")
+		warehousesPath := filepath.Join(dir, "warehouses.json")
+		assert.NoError(t, os.WriteFile(productsPath, []byte(`[{"id":1},{"id":7}]`), 0644))
+		assert.NoError(t, os.WriteFile(warehousesPath, []byte(`[{"id":1}]`), 0644))
+		st := NewJsonStore(productsPath, warehousesPath)
+
+		// act
+		pr, err := st.Create(domain.Product{Name: "product", CodeValue: "C3", WarehouseId: 1})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 8, pr.Id)
+	})
+}
+
+func TestJsonStore_Warehouses(t *testing.T) {
+	t.Run("Success, ids are not reused after delete", func(t *testing.T) {
+		// arrange
+		st, _ := newTestJsonStore(t)
+		last, err := st.CreateWarehouse(domain.Warehouse{Name: "warehouse"})
+		assert.NoError(t, err)
+		_, err = st.DeleteWarehouse(last.Id, false, 0)
+		assert.NoError(t, err)
+
+		// act
+		wr, err := st.CreateWarehouse(domain.Warehouse{Name: "warehouse"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 2, last.Id)
+		assert.Equal(t, 3, wr.Id)
+	})
+
+	t.Run("Success, products move with the delete", func(t *testing.T) {
+		// arrange
+		st, _ := newTestJsonStore(t)
+		target, _ := st.CreateWarehouse(domain.Warehouse{Name: "warehouse"})
+		pr, _ := st.Create(domain.Product{Name: "product", CodeValue: "C1", WarehouseId: 1})
+
+		// act
+		n, err := st.DeleteWarehouse(1, false, target.Id)
+		moved, errRead := st.Read(pr.Id)
+		warehouses, _ := st.ReadWarehouses()
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, errRead)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, target.Id, moved.WarehouseId)
+		assert.Equal(t, []domain.Warehouse{target}, warehouses)
+	})
+
+	t.Run("failed, warehouse with products", func(t *testing.T) {
+		// arrange
+		st, _ := newTestJsonStore(t)
+		_, _ = st.Create(domain.Product{Name: "product", CodeValue: "C1", WarehouseId: 1})
+
+		// act
+		_, err := st.DeleteWarehouse(1, false, 0)
+		_, errMove := st.DeleteWarehouse(1, false, 9)
+		_, errRead := st.ReadWarehouse(1)
+
+		// assert
+		assert.ErrorIs(t, err, ErrWarehouseInUse)
+		assert.ErrorIs(t, errMove, ErrMoveToNotFound)
+		assert.NoError(t, errRead)
+	})
+}
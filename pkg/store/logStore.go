@@ -0,0 +1,418 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+const (
+	opPut    = "put"
+	opDelete = "delete"
+	opBatch  = "batch"
+
+	// DefaultCompactEvery es la cantidad de entradas del journal a partir de la cual se compacta
+	DefaultCompactEvery = 1000
+)
+
+// journalEntry es una mutacion del journal. Las entradas son idempotentes para que
+// reaplicarlas sobre un snapshot que ya las incluye no cambie el resultado
+type journalEntry struct {
+	Op      string          `json:"op"`
+	Id      int             `json:"id"`
+	Product *domain.Product `json:"product,omitempty"`
+	// Batch son las entradas de opBatch. Van en una sola linea del journal, asi una
+	// escritura cortada no deja aplicada solo una parte
+	Batch []journalEntry `json:"batch,omitempty"`
+}
+
+// logStore guarda los productos en memoria, agrega cada mutacion al final de un
+// journal y cada compactEvery entradas vuelca todo a un snapshot con el mismo formato
+// que usa jsonStore, por eso las escrituras no dependen de la cantidad de productos
+type logStore struct {
+	pathToFile       string
+	pathToWarehouses string
+	compactEvery     int
+
+	mu       sync.RWMutex
+	products map[int]domain.Product
+	codes    map[string]int
+	lastID   int
+	journal  *os.File
+	entries  int
+	unlock   func()
+}
+
+// NewLogStore crea un store de products basado en journal. path es el snapshot de
+// productos, el journal se guarda en path + ".log". Los warehouses se guardan en
+// warehousesPath como en jsonStore. El archivo queda bloqueado para otros stores y
+// procesos hasta llamar a Close, porque el indice vive en memoria
+func NewLogStore(path string, warehousesPath string, compactEvery int) StoreInterface {
+	_, err := os.Stat(warehousesPath)
+	if err != nil {
+		panic(err)
+	}
+	if compactEvery <= 0 {
+		compactEvery = DefaultCompactEvery
+	}
+	s := &logStore{
+		pathToFile:       path,
+		pathToWarehouses: warehousesPath,
+		compactEvery:     compactEvery,
+		products:         map[int]domain.Product{},
+		codes:            map[string]int{},
+	}
+	s.unlock, err = acquireFileLock(path, true)
+	if err != nil {
+		panic(err)
+	}
+	if err = s.replay(); err != nil {
+		s.unlock()
+		panic(err)
+	}
+	return s
+}
+
+// journalPath devuelve el archivo donde se agregan las mutaciones
+func (s *logStore) journalPath() string {
+	return s.pathToFile + ".log"
+}
+
+// sequencePath devuelve el archivo que guarda el ultimo id asignado, compartido con jsonStore
+func (s *logStore) sequencePath() string {
+	return s.pathToFile + ".seq"
+}
+
+// replay carga el snapshot, la secuencia y aplica el journal encima
+func (s *logStore) replay() error {
+	file, err := os.ReadFile(s.pathToFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(bytes.TrimSpace(file)) > 0 {
+		var products []domain.Product
+		if err = json.Unmarshal(file, &products); err != nil {
+			return err
+		}
+		for i := range products {
+			s.apply(journalEntry{Op: opPut, Id: products[i].Id, Product: &products[i]})
+		}
+	}
+
+	seq, err := os.ReadFile(s.sequencePath())
+	switch {
+	case err == nil:
+		last, err := strconv.Atoi(strings.TrimSpace(string(seq)))
+		if err != nil {
+			return err
+		}
+		if last > s.lastID {
+			s.lastID = last
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	s.journal, err = os.OpenFile(s.journalPath(), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	valid, err := s.replayJournal(s.journal)
+	if err != nil {
+		s.journal.Close()
+		return err
+	}
+	// descarta una ultima linea incompleta que pudo quedar si el proceso cayo escribiendola
+	if err = s.journal.Truncate(valid); err != nil {
+		s.journal.Close()
+		return err
+	}
+	if _, err = s.journal.Seek(0, io.SeekEnd); err != nil {
+		s.journal.Close()
+		return err
+	}
+	return nil
+}
+
+// replayJournal aplica cada entrada del journal y devuelve hasta que byte es valido
+func (s *logStore) replayJournal(journal io.Reader) (int64, error) {
+	reader := bufio.NewReader(journal)
+	var valid int64
+	for line := 1; ; line++ {
+		raw, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// una linea sin salto final es una escritura cortada
+			return valid, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		var entry journalEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return 0, fmt.Errorf("journal %s line %d: %w", s.journalPath(), line, err)
+		}
+		s.apply(entry)
+		s.entries++
+		valid += int64(len(raw))
+	}
+}
+
+// apply aplica una entrada al indice en memoria
+func (s *logStore) apply(entry journalEntry) {
+	if entry.Op == opBatch {
+		for _, e := range entry.Batch {
+			s.apply(e)
+		}
+		return
+	}
+	if old, ok := s.products[entry.Id]; ok {
+		delete(s.codes, old.CodeValue)
+	}
+	switch entry.Op {
+	case opPut:
+		s.products[entry.Id] = *entry.Product
+		s.codes[entry.Product.CodeValue] = entry.Id
+	case opDelete:
+		delete(s.products, entry.Id)
+	}
+	if entry.Id > s.lastID {
+		s.lastID = entry.Id
+	}
+}
+
+// append escribe la entrada en el journal, la sincroniza a disco y la aplica al indice.
+// Si la escritura falla se recorta el journal para no dejar una linea a medias
+func (s *logStore) append(entry journalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	offset, err := s.journal.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	_, err = s.journal.Write(append(line, '\n'))
+	if err == nil {
+		err = s.journal.Sync()
+	}
+	if err != nil {
+		s.journal.Truncate(offset)
+		s.journal.Seek(offset, io.SeekStart)
+		return err
+	}
+	s.apply(entry)
+	s.entries++
+	if s.entries >= s.compactEvery {
+		// la mutacion ya esta en el journal, si compactar falla se reintenta en la proxima
+		if err := s.compact(); err != nil {
+			log.Printf("store: compacting %s: %v", s.pathToFile, err)
+		}
+	}
+	return nil
+}
+
+// sorted devuelve los productos ordenados por id
+func (s *logStore) sorted() []domain.Product {
+	products := make([]domain.Product, 0, len(s.products))
+	for _, p := range s.products {
+		products = append(products, p)
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].Id < products[j].Id })
+	return products
+}
+
+// compact escribe el snapshot y vacia el journal. Si el proceso cae antes de vaciarlo
+// las entradas se reaplican sobre el snapshot nuevo sin cambiar el resultado
+func (s *logStore) compact() error {
+	if err := writeFileAtomic(s.sequencePath(), []byte(strconv.Itoa(s.lastID)), 0644); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s.sorted())
+	if err != nil {
+		return err
+	}
+	if err = writeFileAtomic(s.pathToFile, data, 0644); err != nil {
+		return err
+	}
+	if err = s.journal.Truncate(0); err != nil {
+		return err
+	}
+	if _, err = s.journal.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err = s.journal.Sync(); err != nil {
+		return err
+	}
+	s.entries = 0
+	return nil
+}
+
+// Compact fuerza una compactacion del journal
+func (s *logStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.compact()
+}
+
+// Close compacta el journal, lo cierra y libera el bloqueo del archivo
+func (s *logStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.compact()
+	if closeErr := s.journal.Close(); err == nil {
+		err = closeErr
+	}
+	s.unlock()
+	return err
+}
+
+func (s *logStore) Read(id int) (domain.Product, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	product, ok := s.products[id]
+	if !ok {
+		return domain.Product{}, ErrNotFound
+	}
+	return product, nil
+}
+
+func (s *logStore) ReadAll() ([]domain.Product, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted(), nil
+}
+
+func (s *logStore) ReadFull(id int) (domain.ProductFull, error) {
+	product, err := s.Read(id)
+	if err != nil {
+		return domain.ProductFull{}, err
+	}
+	return joinWarehouse(product, s.pathToWarehouses)
+}
+
+// checkProduct verifica que ningun otro producto use el code_value y que el warehouse exista
+func (s *logStore) checkProduct(product domain.Product) error {
+	if id, ok := s.codes[product.CodeValue]; ok && id != product.Id {
+		return ErrDuplicateCode
+	}
+	return checkWarehouse(product.WarehouseId, s.pathToWarehouses)
+}
+
+func (s *logStore) Create(product domain.Product) (domain.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	product.Id = 0
+	if err := s.checkProduct(product); err != nil {
+		return domain.Product{}, err
+	}
+	product.Id = s.lastID + 1
+	if err := s.append(journalEntry{Op: opPut, Id: product.Id, Product: &product}); err != nil {
+		return domain.Product{}, err
+	}
+	return product, nil
+}
+
+func (s *logStore) Update(product domain.Product) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.products[product.Id]; !ok {
+		return ErrNotFound
+	}
+	if err := s.checkProduct(product); err != nil {
+		return err
+	}
+	return s.append(journalEntry{Op: opPut, Id: product.Id, Product: &product})
+}
+
+func (s *logStore) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.products[id]; !ok {
+		return ErrNotFound
+	}
+	return s.append(journalEntry{Op: opDelete, Id: id})
+}
+
+func (s *logStore) Exists(codeValue string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.codes[codeValue]
+	return ok
+}
+
+func (s *logStore) ReadWarehouse(id int) (domain.Warehouse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	warehouses, err := loadWarehouses(s.pathToWarehouses)
+	if err != nil {
+		return domain.Warehouse{}, err
+	}
+	i, err := findWarehouse(warehouses, id)
+	if err != nil {
+		return domain.Warehouse{}, err
+	}
+	return warehouses[i], nil
+}
+
+func (s *logStore) ReadWarehouses() ([]domain.Warehouse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return loadWarehouses(s.pathToWarehouses)
+}
+
+func (s *logStore) CreateWarehouse(warehouse domain.Warehouse) (domain.Warehouse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return createWarehouse(s.pathToWarehouses, warehouse)
+}
+
+func (s *logStore) UpdateWarehouse(warehouse domain.Warehouse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return updateWarehouse(s.pathToWarehouses, warehouse)
+}
+
+// DeleteWarehouse agrega al journal la baja o el cambio de warehouse de todos los
+// productos en una sola entrada, que se aplica completa o no se aplica, y despues
+// guarda los warehouses. Si eso falla los productos ya cambiaron pero el warehouse
+// sigue existiendo, ningun producto queda apuntando a un warehouse que no existe
+func (s *logStore) DeleteWarehouse(id int, cascade bool, moveTo int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	warehouses, err := loadWarehouses(s.pathToWarehouses)
+	if err != nil {
+		return 0, err
+	}
+	warehouses, affected, err := removeWarehouse(warehouses, s.sorted(), id, cascade, moveTo)
+	if err != nil {
+		return 0, err
+	}
+	if len(affected) > 0 {
+		batch := make([]journalEntry, len(affected))
+		for i := range affected {
+			batch[i] = journalEntry{Op: opDelete, Id: affected[i].Id}
+			if !cascade {
+				affected[i].WarehouseId = moveTo
+				batch[i] = journalEntry{Op: opPut, Id: affected[i].Id, Product: &affected[i]}
+			}
+		}
+		if err = s.append(journalEntry{Op: opBatch, Batch: batch}); err != nil {
+			return 0, err
+		}
+	}
+	if err = saveWarehouses(s.pathToWarehouses, warehouses); err != nil {
+		return 0, err
+	}
+	return len(affected), nil
+}
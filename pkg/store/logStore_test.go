@@ -0,0 +1,216 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestLogStore crea un store de journal sobre archivos temporales
+func newTestLogStore(t *testing.T, compactEvery int) (*logStore, string) {
+	t.Helper()
+	dir := t.TempDir()
+	productsPath := filepath.Join(dir, "products.json")
+	warehousesPath := filepath.Join(dir, "warehouses.json")
+	assert.NoError(t, os.WriteFile(productsPath, []byte(`[{"id":1,"code_value":"C1","id_warehouse":1}]`), 0644))
+	assert.NoError(t, os.WriteFile(warehousesPath, []byte(`[{"id":1,"name":"Main Warehouse","address":"221 Baker Street"}]`), 0644))
+	return NewLogStore(productsPath, warehousesPath, compactEvery).(*logStore), dir
+}
+
+func TestLogStore_Replay(t *testing.T) {
+	t.Run("Success, mutations survive a restart", func(t *testing.T) {
+		// arrange
+		st, dir := newTestLogStore(t, 100)
+		created, err := st.Create(domain.Product{Name: "product", CodeValue: "C2", WarehouseId: 1})
+		assert.NoError(t, err)
+		assert.NoError(t, st.Update(domain.Product{Id: 1, Name: "updated", CodeValue: "C1", WarehouseId: 1}))
+		assert.NoError(t, st.Delete(created.Id))
+		// se simula una caida sin compactar
+		assert.NoError(t, st.journal.Close())
+		st.unlock()
+
+		// act
+		reopened := NewLogStore(filepath.Join(dir, "products.json"), filepath.Join(dir, "warehouses.json"), 100)
+		defer reopened.Close()
+		products, _ := reopened.ReadAll()
+		next, _ := reopened.Create(domain.Product{Name: "product", CodeValue: "C3", WarehouseId: 1})
+
+		// assert
+		assert.Equal(t, []domain.Product{{Id: 1, Name: "updated", CodeValue: "C1", WarehouseId: 1}}, products)
+		assert.False(t, reopened.Exists("C2"))
+		assert.Equal(t, 3, next.Id)
+	})
+
+	t.Run("Success, torn last entry is discarded", func(t *testing.T) {
+		// arrange
+		st, dir := newTestLogStore(t, 100)
+		_, err := st.Create(domain.Product{Name: "product", CodeValue: "C2", WarehouseId: 1})
+		assert.NoError(t, err)
+		_, err = st.journal.WriteString(`{"op":"put","id":3,"prod`)
+		assert.NoError(t, err)
+		assert.NoError(t, st.journal.Close())
+		st.unlock()
+
+		// act
+		reopened := NewLogStore(filepath.Join(dir, "products.json"), filepath.Join(dir, "warehouses.json"), 100)
+		defer reopened.Close()
+		products, _ := reopened.ReadAll()
+		next, errCreate := reopened.Create(domain.Product{Name: "product", CodeValue: "C3", WarehouseId: 1})
+
+		// assert
+		assert.Len(t, products, 2)
+		assert.NoError(t, errCreate)
+		assert.Equal(t, 3, next.Id)
+	})
+}
+
+func TestLogStore_Compact(t *testing.T) {
+	t.Run("Success, snapshot is written and journal emptied", func(t *testing.T) {
+		// arrange
+		st, dir := newTestLogStore(t, 2)
+		defer st.Close()
+
+		// act
+		_, errFirst := st.Create(domain.Product{Name: "product", CodeValue: "C2", WarehouseId: 1})
+		_, errSecond := st.Create(domain.Product{Name: "product", CodeValue: "C3", WarehouseId: 1})
+		journal, _ := os.ReadFile(filepath.Join(dir, "products.json.log"))
+		var products []domain.Product
+		snapshot, _ := os.ReadFile(filepath.Join(dir, "products.json"))
+		errSnapshot := json.Unmarshal(snapshot, &products)
+
+		// assert
+		assert.NoError(t, errFirst)
+		assert.NoError(t, errSecond)
+		assert.NoError(t, errSnapshot)
+		assert.Empty(t, journal)
+		assert.Len(t, products, 3)
+	})
+
+	t.Run("Success, ids are not reused after compaction", func(t *testing.T) {
+		// arrange
+		st, dir := newTestLogStore(t, 100)
+		created, _ := st.Create(domain.Product{Name: "product", CodeValue: "C2", WarehouseId: 1})
+		assert.NoError(t, st.Delete(created.Id))
+		assert.NoError(t, st.Close())
+
+		// act
+		reopened := NewLogStore(filepath.Join(dir, "products.json"), filepath.Join(dir, "warehouses.json"), 100)
+		defer reopened.Close()
+		next, err := reopened.Create(domain.Product{Name: "product", CodeValue: "C3", WarehouseId: 1})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 3, next.Id)
+	})
+}
+
+func TestLogStore_ReadFull(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// arrange
+		st, _ := newTestLogStore(t, 100)
+		defer st.Close()
+
+		// act
+		pr, err := st.ReadFull(1)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Main Warehouse", pr.WarehouseName)
+	})
+
+	t.Run("failed, not found", func(t *testing.T) {
+		// arrange
+		st, _ := newTestLogStore(t, 100)
+		defer st.Close()
+
+		// act
+		_, err := st.ReadFull(100)
+
+		// assert
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestLogStore_DeleteWarehouse(t *testing.T) {
+	t.Run("Success, moved products survive a restart", func(t *testing.T) {
+		// arrange
+		st, dir := newTestLogStore(t, 100)
+		target, err := st.CreateWarehouse(domain.Warehouse{Name: "warehouse"})
+		assert.NoError(t, err)
+
+		// act
+		n, err := st.DeleteWarehouse(1, false, target.Id)
+		// se simula una caida sin compactar
+		assert.NoError(t, st.journal.Close())
+		st.unlock()
+		reopened := NewLogStore(filepath.Join(dir, "products.json"), filepath.Join(dir, "warehouses.json"), 100)
+		defer reopened.Close()
+		moved, errRead := reopened.Read(1)
+		warehouses, _ := reopened.ReadWarehouses()
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, errRead)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, target.Id, moved.WarehouseId)
+		assert.Equal(t, []domain.Warehouse{target}, warehouses)
+	})
+
+	t.Run("Success, a cut batch is not applied", func(t *testing.T) {
+		// arrange
+		st, dir := newTestLogStore(t, 100)
+		_, err := st.Create(domain.Product{Name: "product", CodeValue: "C2", WarehouseId: 1})
+		assert.NoError(t, err)
+		// se simula una caida a mitad de la escritura de un DeleteWarehouse con cascade
+		_, err = st.journal.WriteString(`{"op":"batch","id":0,"batch":[{"op":"delete","id":1},{"op":"delete","id":2}`)
+		assert.NoError(t, err)
+		assert.NoError(t, st.journal.Close())
+		st.unlock()
+
+		// act
+		reopened := NewLogStore(filepath.Join(dir, "products.json"), filepath.Join(dir, "warehouses.json"), 100)
+		defer reopened.Close()
+		products, err := reopened.ReadAll()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, products, 2)
+	})
+
+	t.Run("Success, cascade deletes the products", func(t *testing.T) {
+		// arrange
+		st, _ := newTestLogStore(t, 100)
+		defer st.Close()
+
+		// act
+		n, err := st.DeleteWarehouse(1, true, 0)
+		_, errRead := st.Read(1)
+		exists := st.Exists("C1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.ErrorIs(t, errRead, ErrNotFound)
+		assert.False(t, exists)
+	})
+
+	t.Run("failed, warehouse with products", func(t *testing.T) {
+		// arrange
+		st, _ := newTestLogStore(t, 100)
+		defer st.Close()
+
+		// act
+		_, err := st.DeleteWarehouse(1, false, 0)
+		_, errMove := st.DeleteWarehouse(1, false, 9)
+		_, errRead := st.ReadWarehouse(1)
+
+		// assert
+		assert.ErrorIs(t, err, ErrWarehouseInUse)
+		assert.ErrorIs(t, errMove, ErrMoveToNotFound)
+		assert.NoError(t, errRead)
+	})
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/bootcamp-go/consignas-go-db.git/internal/domain"
+)
+
+// saveWarehouses guarda los warehouses en el archivo json path
+func saveWarehouses(path string, warehouses []domain.Warehouse) error {
+	bytes, err := json.Marshal(warehouses)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, bytes, 0644)
+}
+
+// findWarehouse devuelve la posicion del warehouse id dentro de warehouses
+func findWarehouse(warehouses []domain.Warehouse, id int) (int, error) {
+	for i, warehouse := range warehouses {
+		if warehouse.Id == id {
+			return i, nil
+		}
+	}
+	return 0, ErrWarehouseNotFound
+}
+
+// createWarehouse agrega warehouse al archivo path con el siguiente id de su
+// secuencia, path + ".seq". Quien llama tiene el bloqueo exclusivo del store
+func createWarehouse(path string, warehouse domain.Warehouse) (domain.Warehouse, error) {
+	warehouses, err := loadWarehouses(path)
+	if err != nil {
+		return domain.Warehouse{}, err
+	}
+	last := 0
+	for _, w := range warehouses {
+		if w.Id > last {
+			last = w.Id
+		}
+	}
+	warehouse.Id, err = reserveID(path+".seq", last)
+	if err != nil {
+		return domain.Warehouse{}, err
+	}
+	if err = saveWarehouses(path, append(warehouses, warehouse)); err != nil {
+		return domain.Warehouse{}, err
+	}
+	return warehouse, nil
+}
+
+// updateWarehouse reemplaza en el archivo path el warehouse con el mismo id. Quien
+// llama tiene el bloqueo exclusivo del store
+func updateWarehouse(path string, warehouse domain.Warehouse) error {
+	warehouses, err := loadWarehouses(path)
+	if err != nil {
+		return err
+	}
+	i, err := findWarehouse(warehouses, warehouse.Id)
+	if err != nil {
+		return err
+	}
+	warehouses[i] = warehouse
+	return saveWarehouses(path, warehouses)
+}
+
+// removeWarehouse revisa que se pueda eliminar el warehouse id con cascade y moveTo y
+// devuelve los warehouses sin el y los productos que lo referencian, que quien llama
+// elimina o mueve antes de guardar los warehouses
+func removeWarehouse(warehouses []domain.Warehouse, products []domain.Product, id int, cascade bool, moveTo int) ([]domain.Warehouse, []domain.Product, error) {
+	i, err := findWarehouse(warehouses, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if moveTo != 0 {
+		if _, err := findWarehouse(warehouses, moveTo); err != nil || moveTo == id {
+			return nil, nil, ErrMoveToNotFound
+		}
+	}
+	var referencing []domain.Product
+	for _, p := range products {
+		if p.WarehouseId == id {
+			referencing = append(referencing, p)
+		}
+	}
+	if len(referencing) > 0 && !cascade && moveTo == 0 {
+		return nil, nil, ErrWarehouseInUse
+	}
+	remaining := append(warehouses[:i:i], warehouses[i+1:]...)
+	return remaining, referencing, nil
+}
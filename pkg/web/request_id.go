@@ -0,0 +1,43 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderRequestID es el header con el id del request, se respeta el que manda el
+// cliente o el proxy y se devuelve en la respuesta
+const HeaderRequestID = "X-Request-ID"
+
+const requestIDKey = "request_id"
+
+// RequestID asigna un id a cada request, para encontrarlo en los logs a partir de
+// la respuesta
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(HeaderRequestID)
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+		ctx.Set(requestIDKey, id)
+		ctx.Header(HeaderRequestID, id)
+		ctx.Next()
+	}
+}
+
+// GetRequestID devuelve el id que RequestID asigno al request, vacio si no paso
+// por el middleware
+func GetRequestID(ctx *gin.Context) string {
+	return ctx.GetString(requestIDKey)
+}
+
+// newRequestID genera un id aleatorio de 16 bytes en hexadecimal
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -2,16 +2,35 @@ package web
 
 import (
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-type errorResponse struct {
-	Status  int    `json:"status"`
-	Code    string `json:"code"`
+// ContentTypeProblem es el content type de las respuestas de error, RFC 7807
+const ContentTypeProblem = "application/problem+json"
+
+// FieldError es un problema con un campo del request, Field es la ruta del campo
+// en el json, por ejemplo "expiration"
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
+// Problem es el cuerpo de una respuesta de error segun RFC 7807. Type y Code
+// identifican el tipo de error y no cambian, Detail es el mensaje para mostrar
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
 type response struct {
 	Data interface{} `json:"data"`
 }
@@ -23,11 +42,69 @@ func Success(ctx *gin.Context, status int, data interface{}) {
 	})
 }
 
-// Failure escribe una respuesta fallida
+// Pagination describe la pagina de un listado. Total cuenta todos los elementos que
+// cumplen los filtros, Next y Prev son las urls de las paginas vecinas y no estan
+// cuando no hay pagina
+type Pagination struct {
+	Total  int    `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+	Next   string `json:"next,omitempty"`
+	Prev   string `json:"prev,omitempty"`
+}
+
+type pageResponse struct {
+	Data       interface{} `json:"data"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+// SuccessPage escribe una pagina de un listado
+func SuccessPage(ctx *gin.Context, status int, data interface{}, pagination Pagination) {
+	ctx.JSON(status, pageResponse{
+		Data:       data,
+		Pagination: pagination,
+	})
+}
+
+// PageURL devuelve la url del request con los parametros de params cambiados, un
+// valor vacio saca el parametro. Sirve para armar los links de Pagination
+func PageURL(ctx *gin.Context, params map[string]string) string {
+	query := ctx.Request.URL.Query()
+	for name, value := range params {
+		if value == "" {
+			query.Del(name)
+		} else {
+			query.Set(name, value)
+		}
+	}
+	u := url.URL{Path: ctx.Request.URL.Path, RawQuery: query.Encode()}
+	return u.String()
+}
+
+// Failure escribe una respuesta fallida, el code es el texto del status, por
+// ejemplo bad_request
 func Failure(ctx *gin.Context, status int, err error) {
-	ctx.JSON(status, errorResponse{
-		Message: err.Error(),
-		Status:  status,
-		Code:    http.StatusText(status),
+	FailureCode(ctx, status, StatusCode(status), err)
+}
+
+// FailureCode escribe una respuesta fallida con el code del error y los problemas
+// de cada campo, si los hay
+func FailureCode(ctx *gin.Context, status int, code string, err error, fields ...FieldError) {
+	ctx.Header("Content-Type", ContentTypeProblem)
+	ctx.JSON(status, Problem{
+		Type:      "/problems/" + code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    err.Error(),
+		Instance:  ctx.Request.URL.Path,
+		Code:      code,
+		RequestID: GetRequestID(ctx),
+		Errors:    fields,
 	})
 }
+
+// StatusCode devuelve el texto del status en minusculas y con guiones bajos, por
+// ejemplo not_found
+func StatusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
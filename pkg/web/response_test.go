@@ -0,0 +1,71 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailure(t *testing.T) {
+	t.Run("Success, problem with the status code and a generated request id", func(t *testing.T) {
+		// arrange
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.Use(RequestID())
+		r.GET("/products/:id", func(c *gin.Context) {
+			Failure(c, http.StatusBadRequest, errors.New("invalid id"))
+		})
+		req := httptest.NewRequest(http.MethodGet, "/products/abc", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		var problem Problem
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, ContentTypeProblem, res.Header().Get("Content-Type"))
+		assert.Equal(t, Problem{
+			Type:      "/problems/bad_request",
+			Title:     "Bad Request",
+			Status:    http.StatusBadRequest,
+			Detail:    "invalid id",
+			Instance:  "/products/abc",
+			Code:      "bad_request",
+			RequestID: res.Header().Get(HeaderRequestID),
+		}, problem)
+		assert.Len(t, problem.RequestID, 32)
+	})
+}
+
+func TestSuccessPage(t *testing.T) {
+	t.Run("Success, links keep the other parameters", func(t *testing.T) {
+		// arrange
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.GET("/products", func(c *gin.Context) {
+			SuccessPage(c, http.StatusOK, []int{1, 2}, Pagination{
+				Total: 5,
+				Limit: 2,
+				Next:  PageURL(c, map[string]string{"offset": "2"}),
+				Prev:  PageURL(c, map[string]string{"offset": ""}),
+			})
+		})
+		req := httptest.NewRequest(http.MethodGet, "/products?limit=2&is_published=true&offset=0", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(res, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.JSONEq(t, `{"data":[1,2],"pagination":{"total":5,"limit":2,"offset":0,
+			"next":"/products?is_published=true&limit=2&offset=2","prev":"/products?is_published=true&limit=2"}}`, res.Body.String())
+	})
+}